@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/kuhlman-labs/restaurant-recommender/config"
+	restaurantrecommender "github.com/kuhlman-labs/restaurant-recommender/restaurant-recommender"
+)
+
+// runCommand runs the named subcommand with its arguments, cancelling it on
+// interrupt or SIGTERM.
+func runCommand(db *sql.DB, name string, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch name {
+	case "import":
+		return runImport(ctx, db, args)
+	case "export":
+		return runExport(ctx, db, args)
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
+
+// runConfig handles the config subcommand. loadErr is the error from loading
+// the configuration, reported after printing it.
+//
+//	restaurant-recommender [flags] config print
+func runConfig(cfg config.Config, loadErr error, args []string) error {
+	if len(args) != 1 || args[0] != "print" {
+		return errors.New("usage: config print")
+	}
+	if err := cfg.Print(os.Stdout); err != nil {
+		return err
+	}
+	if loadErr != nil {
+		return fmt.Errorf("invalid configuration:\n%w", loadErr)
+	}
+	return nil
+}
+
+// runImport bulk imports restaurants from a CSV, JSON lines or OpenStreetMap
+// file and prints the per-row report as JSON.
+//
+//	restaurant-recommender import [-format csv|json|overpass|osm-pbf] [-dry-run] FILE
+func runImport(ctx context.Context, db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", "", "input format: csv, json, overpass or osm-pbf (default: from the file extension)")
+	dryRun := fs.Bool("dry-run", false, "validate and report without writing to the database")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: import [-format csv|json|overpass|osm-pbf] [-dry-run] FILE")
+	}
+
+	path := fs.Arg(0)
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			*format = restaurantrecommender.ImportFormatCSV
+		case ".pbf":
+			*format = restaurantrecommender.ImportFormatOSMPBF
+		default:
+			*format = restaurantrecommender.ImportFormatJSON
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	report, err := restaurantrecommender.ImportRestaurants(ctx, db, f, *format, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	if report.RolledBack {
+		failed := report.Rows[len(report.Rows)-1]
+		return fmt.Errorf("import rolled back: line %d failed: %s", failed.Line, failed.Error)
+	}
+	if !report.OK() {
+		return fmt.Errorf("import finished with %d invalid and %d failed rows", report.Invalid, report.Failed)
+	}
+	return nil
+}
+
+// runExport writes the restaurant catalogue as CSV, JSON lines or XML.
+//
+//	restaurant-recommender export [-format csv|json|xml] [-columns name,style,...] [-o FILE]
+func runExport(ctx context.Context, db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", restaurantrecommender.ExportFormatCSV, "output format: csv, json or xml")
+	columns := fs.String("columns", "", "comma-separated fields to export (default: all)")
+	output := fs.String("o", "", "output file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return errors.New("usage: export [-format csv|json|xml] [-columns name,style,...] [-o FILE]")
+	}
+
+	var cols []string
+	if *columns != "" {
+		cols = strings.Split(*columns, ",")
+	}
+	if err := restaurantrecommender.ValidateExport(*format, cols); err != nil {
+		return err
+	}
+
+	w := os.Stdout
+	var f *os.File
+	if *output != "" {
+		var err error
+		if f, err = os.Create(*output); err != nil {
+			return err
+		}
+		// Closes the file when the export fails; on success it is closed
+		// below, so that an error writing it to disk is reported.
+		defer f.Close()
+		w = f
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := restaurantrecommender.ExportRestaurants(ctx, db, bw, *format, cols); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	if f != nil {
+		return f.Close()
+	}
+	return nil
+}
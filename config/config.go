@@ -0,0 +1,323 @@
+// Package config loads the service configuration from a YAML file,
+// environment variables and command-line flags.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted replaces secret values when a configuration is printed.
+const Redacted = "[redacted]"
+
+// Auth modes for connecting to the database.
+const (
+	// AuthSQL signs in with a SQL login in User and Password.
+	AuthSQL = "sql"
+	// AuthServicePrincipal signs in as the Entra ID application whose client
+	// ID is User and client secret is Password.
+	AuthServicePrincipal = "service-principal"
+	// AuthManagedIdentity uses an access token for the host's managed
+	// identity; User optionally selects a user-assigned identity's client ID.
+	AuthManagedIdentity = "managed-identity"
+	// AuthConnectionString passes ConnectionString to the driver unchanged.
+	AuthConnectionString = "connection-string"
+)
+
+// Tracing exporters.
+const (
+	TracingNone   = "none"
+	TracingOTLP   = "otlp"
+	TracingStdout = "stdout"
+)
+
+// Log output formats.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// Config is the complete service configuration. Each setting can be given in
+// the config file under its yaml key, in the environment variable named by
+// its env tag, or as a flag named by its dotted yaml path (e.g. -server.listenAddr).
+type Config struct {
+	DB       DBConfig       `yaml:"db"`
+	Server   ServerConfig   `yaml:"server"`
+	Cache    CacheConfig    `yaml:"cache"`
+	QueryLog QueryLogConfig `yaml:"queryLog"`
+	Logging  LoggingConfig  `yaml:"logging"`
+	Tracing  TracingConfig  `yaml:"tracing"`
+	Features FeatureConfig  `yaml:"features"`
+}
+
+// DBConfig configures the Azure SQL connection.
+type DBConfig struct {
+	Server   string `yaml:"server" env:"DB_SERVER"`
+	Port     int    `yaml:"port" env:"DB_PORT"`
+	Name     string `yaml:"name" env:"DB_NAME"`
+	Auth     string `yaml:"auth" env:"DB_AUTH"`
+	User     string `yaml:"user" env:"DB_USER"`
+	Password string `yaml:"password" env:"DB_PASS" secret:"true"`
+	// ConnectionString is only used by the connection-string auth mode.
+	ConnectionString string        `yaml:"connectionString" env:"DB_CONNECTION_STRING" secret:"true"`
+	QueryTimeout     time.Duration `yaml:"queryTimeout" env:"DB_QUERY_TIMEOUT"`
+}
+
+// ServerConfig configures the HTTP and gRPC servers.
+type ServerConfig struct {
+	ListenAddr string `yaml:"listenAddr" env:"LISTEN_ADDR"`
+	// GRPCListenAddr is where the gRPC server listens. Empty disables it.
+	GRPCListenAddr    string        `yaml:"grpcListenAddr" env:"GRPC_LISTEN_ADDR"`
+	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	ReadTimeout       time.Duration `yaml:"readTimeout" env:"HTTP_READ_TIMEOUT"`
+	WriteTimeout      time.Duration `yaml:"writeTimeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout       time.Duration `yaml:"idleTimeout" env:"HTTP_IDLE_TIMEOUT"`
+	// ShutdownDelay is how long readiness fails before the server stops
+	// accepting connections, giving load balancers time to notice.
+	ShutdownDelay   time.Duration `yaml:"shutdownDelay" env:"SHUTDOWN_DELAY"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" env:"SHUTDOWN_TIMEOUT"`
+	AdminToken      string        `yaml:"adminToken" env:"ADMIN_TOKEN" secret:"true"`
+}
+
+// CacheConfig configures the catalogue cache. A zero TTL disables it.
+type CacheConfig struct {
+	TTL             time.Duration `yaml:"ttl" env:"CACHE_TTL"`
+	RefreshInterval time.Duration `yaml:"refreshInterval" env:"CACHE_REFRESH_INTERVAL"`
+}
+
+// QueryLogConfig configures the background query-log writer.
+type QueryLogConfig struct {
+	QueueSize      int           `yaml:"queueSize" env:"QUERY_LOG_QUEUE_SIZE"`
+	BatchSize      int           `yaml:"batchSize" env:"QUERY_LOG_BATCH_SIZE"`
+	FlushInterval  time.Duration `yaml:"flushInterval" env:"QUERY_LOG_FLUSH_INTERVAL"`
+	EnqueueTimeout time.Duration `yaml:"enqueueTimeout" env:"QUERY_LOG_ENQUEUE_TIMEOUT"`
+}
+
+// LoggingConfig configures the service log output.
+type LoggingConfig struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL"`
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+// TracingConfig configures OpenTelemetry tracing.
+type TracingConfig struct {
+	// Exporter is none, otlp (OTLP over HTTP) or stdout.
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER"`
+	// Endpoint is the OTLP collector URL, e.g. http://collector:4318. When
+	// empty the standard OTEL_EXPORTER_OTLP_* variables apply.
+	Endpoint    string  `yaml:"endpoint" env:"TRACING_ENDPOINT"`
+	ServiceName string  `yaml:"serviceName" env:"TRACING_SERVICE_NAME"`
+	SampleRatio float64 `yaml:"sampleRatio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+// FeatureConfig switches optional parts of the service on or off.
+type FeatureConfig struct {
+	// Admin exposes the restaurant administration endpoints when
+	// Server.AdminToken is also set.
+	Admin bool `yaml:"admin" env:"FEATURE_ADMIN"`
+	// QueryLog records recommendation queries in the query_logs table.
+	QueryLog bool `yaml:"queryLog" env:"FEATURE_QUERY_LOG"`
+	// Metrics serves Prometheus metrics at /metrics.
+	Metrics bool `yaml:"metrics" env:"FEATURE_METRICS"`
+}
+
+// Default returns the configuration used for anything not set elsewhere.
+func Default() Config {
+	return Config{
+		DB: DBConfig{
+			Port:         1433,
+			Auth:         AuthServicePrincipal,
+			QueryTimeout: 5 * time.Second,
+		},
+		Server: ServerConfig{
+			ListenAddr:        ":80",
+			GRPCListenAddr:    ":50051",
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			// Exports stream the whole catalogue, so writes get longer than reads.
+			WriteTimeout:    60 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutdownDelay:   5 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+		},
+		// The cache is off by default so that recommendations are filtered in
+		// the database, which always sees the current catalogue.
+		Cache: CacheConfig{},
+		QueryLog: QueryLogConfig{
+			QueueSize:     1000,
+			BatchSize:     100,
+			FlushInterval: time.Second,
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: LogFormatText,
+		},
+		Tracing: TracingConfig{
+			Exporter:    TracingNone,
+			ServiceName: "restaurant-recommender",
+			SampleRatio: 1,
+		},
+		Features: FeatureConfig{
+			Admin:    true,
+			QueryLog: true,
+			Metrics:  true,
+		},
+	}
+}
+
+// Validate reports every problem with the configuration.
+func (c Config) Validate() error {
+	var errs []error
+	required := func(key, value string) {
+		if value == "" {
+			errs = append(errs, fmt.Errorf("%s is required", key))
+		}
+	}
+	nonNegative := func(key string, d time.Duration) {
+		if d < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative", key))
+		}
+	}
+	positive := func(key string, n int) {
+		if n <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive", key))
+		}
+	}
+
+	switch c.DB.Auth {
+	case AuthSQL, AuthServicePrincipal:
+		required("db.user", c.DB.User)
+		required("db.password", c.DB.Password)
+	case AuthManagedIdentity, AuthConnectionString:
+	default:
+		errs = append(errs, fmt.Errorf("db.auth %q must be %s, %s, %s or %s",
+			c.DB.Auth, AuthSQL, AuthServicePrincipal, AuthManagedIdentity, AuthConnectionString))
+	}
+	if c.DB.Auth == AuthConnectionString {
+		required("db.connectionString", c.DB.ConnectionString)
+	} else {
+		required("db.server", c.DB.Server)
+		required("db.name", c.DB.Name)
+		if c.DB.Port <= 0 || c.DB.Port > 65535 {
+			errs = append(errs, fmt.Errorf("db.port %d is out of range", c.DB.Port))
+		}
+	}
+	nonNegative("db.queryTimeout", c.DB.QueryTimeout)
+
+	required("server.listenAddr", c.Server.ListenAddr)
+	if c.Server.GRPCListenAddr != "" && c.Server.GRPCListenAddr == c.Server.ListenAddr {
+		errs = append(errs, fmt.Errorf("server.grpcListenAddr %q must differ from server.listenAddr", c.Server.GRPCListenAddr))
+	}
+	nonNegative("server.readHeaderTimeout", c.Server.ReadHeaderTimeout)
+	nonNegative("server.readTimeout", c.Server.ReadTimeout)
+	nonNegative("server.writeTimeout", c.Server.WriteTimeout)
+	nonNegative("server.idleTimeout", c.Server.IdleTimeout)
+	nonNegative("server.shutdownDelay", c.Server.ShutdownDelay)
+	nonNegative("server.shutdownTimeout", c.Server.ShutdownTimeout)
+
+	nonNegative("cache.ttl", c.Cache.TTL)
+	nonNegative("cache.refreshInterval", c.Cache.RefreshInterval)
+
+	positive("queryLog.queueSize", c.QueryLog.QueueSize)
+	positive("queryLog.batchSize", c.QueryLog.BatchSize)
+	if c.QueryLog.FlushInterval <= 0 {
+		errs = append(errs, errors.New("queryLog.flushInterval must be positive"))
+	}
+	nonNegative("queryLog.enqueueTimeout", c.QueryLog.EnqueueTimeout)
+
+	if _, err := c.Logging.level(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Logging.Format != LogFormatText && c.Logging.Format != LogFormatJSON {
+		errs = append(errs, fmt.Errorf("logging.format %q must be %s or %s", c.Logging.Format, LogFormatText, LogFormatJSON))
+	}
+
+	switch c.Tracing.Exporter {
+	case TracingNone, TracingOTLP, TracingStdout:
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter %q must be %s, %s or %s", c.Tracing.Exporter, TracingNone, TracingOTLP, TracingStdout))
+	}
+	required("tracing.serviceName", c.Tracing.ServiceName)
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sampleRatio %v must be between 0 and 1", c.Tracing.SampleRatio))
+	}
+	return errors.Join(errs...)
+}
+
+// DSN returns the driver connection string for the configured auth mode.
+// It contains credentials and must not be logged.
+func (c DBConfig) DSN() string {
+	if c.Auth == AuthConnectionString {
+		return c.ConnectionString
+	}
+
+	u := &url.URL{
+		Scheme: "sqlserver",
+		Host:   net.JoinHostPort(c.Server, strconv.Itoa(c.Port)),
+	}
+	query := url.Values{"database": {c.Name}}
+	switch c.Auth {
+	case AuthSQL:
+		u.User = url.UserPassword(c.User, c.Password)
+	case AuthServicePrincipal:
+		u.User = url.UserPassword(c.User, c.Password)
+		query.Set("fedauth", "ActiveDirectoryServicePrincipal")
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// String describes the database connection without any credentials.
+func (c DBConfig) String() string {
+	if c.Auth == AuthConnectionString {
+		return "database from connection string"
+	}
+	return fmt.Sprintf("database %s on %s:%d (%s auth)", c.Name, c.Server, c.Port, c.Auth)
+}
+
+// Redacted returns a copy of the configuration with secrets replaced, for printing.
+func (c Config) Redacted() Config {
+	for _, s := range settings(&c) {
+		if s.secret && !s.field.IsZero() {
+			s.field.SetString(Redacted)
+		}
+	}
+	return c
+}
+
+// Print writes the configuration as YAML with secrets redacted.
+func (c Config) Print(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c.Redacted()); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// level parses the configured log level.
+func (c LoggingConfig) level() (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Level)); err != nil {
+		return level, fmt.Errorf("logging.level %q must be debug, info, warn or error", c.Level)
+	}
+	return level, nil
+}
+
+// Handler returns a slog handler writing to w in the configured format and level.
+func (c LoggingConfig) Handler(w io.Writer) slog.Handler {
+	level, _ := c.level()
+	opts := &slog.HandlerOptions{Level: level}
+	if c.Format == LogFormatJSON {
+		return slog.NewJSONHandler(w, opts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable giving the config file path when
+// the -config flag is not used.
+const FileEnv = "CONFIG_FILE"
+
+// setting is a single configurable field of Config.
+type setting struct {
+	key    string // dotted yaml path, also the flag name
+	env    string
+	secret bool
+	field  reflect.Value
+}
+
+// settings lists the configurable fields of c, addressable so they can be set.
+func settings(c *Config) []setting {
+	var out []setting
+	var walk func(prefix string, v reflect.Value)
+	walk = func(prefix string, v reflect.Value) {
+		t := v.Type()
+		for i := range t.NumField() {
+			f := t.Field(i)
+			key := prefix + f.Tag.Get("yaml")
+			if f.Type.Kind() == reflect.Struct {
+				walk(key+".", v.Field(i))
+				continue
+			}
+			out = append(out, setting{
+				key:    key,
+				env:    f.Tag.Get("env"),
+				secret: f.Tag.Get("secret") == "true",
+				field:  v.Field(i),
+			})
+		}
+	}
+	walk("", reflect.ValueOf(c).Elem())
+	return out
+}
+
+// set parses value into the setting's field.
+func (s setting) set(value string) error {
+	switch s.field.Interface().(type) {
+	case string:
+		s.field.SetString(value)
+	case bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: invalid boolean %q", s.key, value)
+		}
+		s.field.SetBool(b)
+	case int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s: invalid integer %q", s.key, value)
+		}
+		s.field.SetInt(int64(n))
+	case float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s: invalid number %q", s.key, value)
+		}
+		s.field.SetFloat(f)
+	case time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s: invalid duration %q", s.key, value)
+		}
+		s.field.SetInt(int64(d))
+	default:
+		return fmt.Errorf("%s: unsupported type %s", s.key, s.field.Type())
+	}
+	return nil
+}
+
+// Load builds the configuration from, in increasing precedence, the defaults,
+// the config file, environment variables and flags parsed from args. It
+// returns the arguments left after the flags, such as a subcommand, even when
+// the configuration is invalid, along with every problem found, so that
+// "config print" can show what was loaded and why it is invalid.
+func Load(args []string, getenv func(string) string) (Config, []string, error) {
+	cfg := Default()
+
+	// Flags are parsed first to find the config file, but applied last.
+	fs := flag.NewFlagSet("restaurant-recommender", flag.ContinueOnError)
+	path := fs.String("config", "", "YAML or TOML config file (default: $"+FileEnv+")")
+	flags := map[string]*string{}
+	for _, s := range settings(&cfg) {
+		usage := "sets " + s.key
+		if s.env != "" {
+			usage += " (env " + s.env + ")"
+		}
+		flags[s.key] = fs.String(s.key, "", usage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return cfg, fs.Args(), err
+	}
+
+	var errs []error
+	if *path == "" {
+		*path = getenv(FileEnv)
+	}
+	if *path != "" {
+		if err := loadFile(&cfg, *path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, s := range settings(&cfg) {
+		if s.env == "" {
+			continue
+		}
+		if value := getenv(s.env); value != "" {
+			if err := s.set(value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", s.env, err))
+			}
+		}
+	}
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for _, s := range settings(&cfg) {
+		if set[s.key] {
+			if err := s.set(*flags[s.key]); err != nil {
+				errs = append(errs, fmt.Errorf("-%w", err))
+			}
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	return cfg, fs.Args(), errors.Join(errs...)
+}
+
+// loadFile overlays the config file at path onto cfg, rejecting unknown keys.
+// Files ending in .toml are TOML; any other file is YAML.
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		if data, err = tomlToYAML(data); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s: %s", path, strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+	return nil
+}
+
+// tomlToYAML converts a TOML document to YAML, so that TOML files are decoded
+// with the same keys, duration syntax and unknown-key checks as YAML files.
+func tomlToYAML(data []byte) ([]byte, error) {
+	var doc map[string]any
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc) == 0 {
+		return nil, nil
+	}
+	return yaml.Marshal(doc)
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// env returns a getenv function backed by vars.
+func env(vars map[string]string) func(string) string {
+	return func(name string) string { return vars[name] }
+}
+
+// writeFile writes a YAML config file into a temporary directory.
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	return writeFileNamed(t, "config.yaml", content)
+}
+
+// writeFileNamed writes a config file with the given name into a temporary directory.
+func writeFileNamed(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestLoad tests that flags override the environment, which overrides the file.
+func TestLoad(t *testing.T) {
+	path := writeFile(t, `
+db:
+  server: file.example.net
+  name: restaurants
+  user: app
+  password: from-file
+server:
+  listenAddr: ":9090"
+cache:
+  ttl: 2m
+features:
+  queryLog: false
+`)
+	vars := map[string]string{
+		FileEnv:                path,
+		"DB_PASS":              "from-env",
+		"CACHE_TTL":            "3m",
+		"TRACING_SAMPLE_RATIO": "0.25",
+	}
+
+	cfg, args, err := Load([]string{"-cache.ttl=4m", "-db.port", "1434", "export", "-format", "json"}, env(vars))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if cfg.DB.Server != "file.example.net" || cfg.Server.ListenAddr != ":9090" || cfg.Features.QueryLog {
+		t.Errorf("expected file settings to apply, got %+v", cfg)
+	}
+	if cfg.DB.Password != "from-env" {
+		t.Errorf("expected environment to override the file, got %q", cfg.DB.Password)
+	}
+	if cfg.Cache.TTL != 4*time.Minute || cfg.DB.Port != 1434 {
+		t.Errorf("expected flags to override the environment, got ttl %v and port %d", cfg.Cache.TTL, cfg.DB.Port)
+	}
+	if cfg.Tracing.SampleRatio != 0.25 {
+		t.Errorf("expected sample ratio from the environment, got %v", cfg.Tracing.SampleRatio)
+	}
+	if cfg.QueryLog.BatchSize != 100 || !cfg.Features.Admin {
+		t.Errorf("expected defaults for unset settings, got %+v", cfg)
+	}
+	if want := []string{"export", "-format", "json"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("expected remaining args %v, got %v", want, args)
+	}
+}
+
+// TestLoad_ConfigFlag tests that -config takes precedence over CONFIG_FILE.
+func TestLoad_ConfigFlag(t *testing.T) {
+	path := writeFile(t, "db: {server: flag.example.net, name: r, user: u, password: p}\n")
+	vars := map[string]string{FileEnv: filepath.Join(t.TempDir(), "missing.yaml")}
+
+	cfg, _, err := Load([]string{"-config", path}, env(vars))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.DB.Server != "flag.example.net" {
+		t.Errorf("expected config from the -config file, got %q", cfg.DB.Server)
+	}
+}
+
+// TestLoad_TOML tests that .toml files are read with the same keys as YAML.
+func TestLoad_TOML(t *testing.T) {
+	path := writeFileNamed(t, "config.toml", `
+[db]
+server = "toml.example.net"
+name = "restaurants"
+user = "app"
+password = "from-file"
+queryTimeout = "7s"
+
+[cache]
+ttl = "2m"
+
+[features]
+queryLog = false
+`)
+	cfg, _, err := Load([]string{"-config", path}, env(nil))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.DB.Server != "toml.example.net" || cfg.DB.QueryTimeout != 7*time.Second || cfg.Cache.TTL != 2*time.Minute || cfg.Features.QueryLog {
+		t.Errorf("expected settings from the TOML file, got %+v", cfg)
+	}
+}
+
+// TestLoad_InvalidKeepsArgs tests that an invalid configuration still returns
+// the subcommand, and every problem with the configuration.
+func TestLoad_InvalidKeepsArgs(t *testing.T) {
+	cfg, args, err := Load([]string{"-db.port=1434", "config", "print"}, env(map[string]string{"CACHE_TTL": "soon"}))
+	if want := []string{"config", "print"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("expected remaining args %v, got %v", want, args)
+	}
+	for _, want := range []string{`invalid duration "soon"`, "db.server is required"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error containing %q, got %v", want, err)
+		}
+	}
+	if cfg.DB.Port != 1434 {
+		t.Errorf("expected flags to apply despite the errors, got port %d", cfg.DB.Port)
+	}
+}
+
+// TestLoad_Errors tests that bad files, values and configurations are reported.
+func TestLoad_Errors(t *testing.T) {
+	required := map[string]string{"DB_SERVER": "s", "DB_NAME": "n", "DB_USER": "u", "DB_PASS": "p"}
+	with := func(extra map[string]string) map[string]string {
+		vars := map[string]string{}
+		for k, v := range required {
+			vars[k] = v
+		}
+		for k, v := range extra {
+			vars[k] = v
+		}
+		return vars
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		vars map[string]string
+		want string
+	}{
+		{"missing required", nil, nil, "db.server is required"},
+		{"bad env value", nil, with(map[string]string{"CACHE_TTL": "soon"}), `CACHE_TTL: cache.ttl: invalid duration "soon"`},
+		{"bad flag value", []string{"-features.admin=maybe"}, required, `-features.admin: invalid boolean "maybe"`},
+		{"unknown flag", []string{"-nope"}, required, "flag provided but not defined: -nope"},
+		{"unknown file key", nil, with(map[string]string{FileEnv: writeFile(t, "cache:\n  size: 10\n")}), "field size not found"},
+		{"unknown TOML key", nil, with(map[string]string{FileEnv: writeFileNamed(t, "config.toml", "[cache]\nsize = 10\n")}), "field size not found"},
+		{"malformed TOML", nil, with(map[string]string{FileEnv: writeFileNamed(t, "config.toml", "[cache\n")}), "config.toml: "},
+		{"invalid setting", nil, with(map[string]string{"LOG_FORMAT": "xml"}), `logging.format "xml"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Load(tt.args, env(tt.vars))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// TestSettings tests that every setting can be set from the environment.
+func TestSettings(t *testing.T) {
+	cfg := Default()
+	seen := map[string]bool{}
+	for _, s := range settings(&cfg) {
+		if s.env == "" {
+			t.Errorf("%s has no environment variable", s.key)
+		}
+		if seen[s.env] {
+			t.Errorf("%s is used by more than one setting", s.env)
+		}
+		seen[s.env] = true
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	mssql "github.com/microsoft/go-mssqldb"
+	"github.com/microsoft/go-mssqldb/azuread"
+
+	"github.com/kuhlman-labs/restaurant-recommender/config"
+)
+
+// sqlDatabaseScope is the Entra ID scope for Azure SQL access tokens.
+const sqlDatabaseScope = "https://database.windows.net/.default"
+
+// openDB connects to the configured database using its auth mode. Errors
+// describe the connection without its credentials.
+func openDB(cfg config.DBConfig) (*sql.DB, error) {
+	var db *sql.DB
+	switch cfg.Auth {
+	case config.AuthManagedIdentity:
+		connector, err := managedIdentityConnector(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("connecting to %s: %w", cfg, err)
+		}
+		db = sql.OpenDB(connector)
+	default:
+		// The azuread driver handles SQL logins as well as its fedauth modes.
+		var err error
+		db, err = sql.Open(azuread.DriverName, cfg.DSN())
+		if err != nil {
+			return nil, fmt.Errorf("opening %s: %w", cfg, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connecting to %s: %w", cfg, err)
+	}
+	slog.Info("Connected to database", "database", cfg.String())
+	return db, nil
+}
+
+// managedIdentityConnector authenticates each new connection with an access
+// token for the host's managed identity, or the user-assigned identity whose
+// client ID is cfg.User.
+func managedIdentityConnector(cfg config.DBConfig) (driver.Connector, error) {
+	var opts azidentity.ManagedIdentityCredentialOptions
+	if cfg.User != "" {
+		opts.ID = azidentity.ClientID(cfg.User)
+	}
+	cred, err := azidentity.NewManagedIdentityCredential(&opts)
+	if err != nil {
+		return nil, fmt.Errorf("creating managed identity credential: %w", err)
+	}
+	return mssql.NewAccessTokenConnector(cfg.DSN(), tokenProvider(cred))
+}
+
+// tokenProvider returns a function fetching Azure SQL access tokens from cred,
+// which caches them until they near expiry.
+func tokenProvider(cred azcore.TokenCredential) func() (string, error) {
+	return func() (string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		token, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{sqlDatabaseScope}})
+		if err != nil {
+			return "", fmt.Errorf("getting managed identity token: %w", err)
+		}
+		return token.Token, nil
+	}
+}
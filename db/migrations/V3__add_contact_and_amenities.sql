@@ -0,0 +1,24 @@
+-- Add contact details and amenity information to restaurants.
+ALTER TABLE restaurants ADD
+  phone NVARCHAR(50) NOT NULL DEFAULT '',
+  website NVARCHAR(255) NOT NULL DEFAULT '',
+  email NVARCHAR(255) NOT NULL DEFAULT '',
+  seatingCapacity INT NOT NULL DEFAULT 0,
+  parking BIT NOT NULL DEFAULT 0,
+  wifi BIT NOT NULL DEFAULT 0,
+  wheelchairAccessible BIT NOT NULL DEFAULT 0;
+
+UPDATE restaurants
+SET phone = '+1 555 0100', website = 'https://www.pizzahut.com', email = 'info@pizzahut.example',
+    seatingCapacity = 80, parking = 1, wifi = 1, wheelchairAccessible = 1
+WHERE name = 'Pizza Hut';
+
+UPDATE restaurants
+SET phone = '+1 555 0101', website = 'https://www.tacobell.com', email = 'info@tacobell.example',
+    seatingCapacity = 40, parking = 1, wifi = 0, wheelchairAccessible = 1
+WHERE name = 'Taco Bell';
+
+UPDATE restaurants
+SET phone = '+82 2 555 0102', website = '', email = 'hello@seoulbites.example',
+    seatingCapacity = 24, parking = 0, wifi = 1, wheelchairAccessible = 0
+WHERE name = 'Seoul Bites';
@@ -0,0 +1,5 @@
+-- Give every restaurant a stable, opaque identifier for use in the public API.
+ALTER TABLE restaurants ADD publicId UNIQUEIDENTIFIER NOT NULL
+  CONSTRAINT DF_restaurants_publicId DEFAULT NEWID() WITH VALUES;
+
+CREATE UNIQUE INDEX UX_restaurants_publicId ON restaurants (publicId);
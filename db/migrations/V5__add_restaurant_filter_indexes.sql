@@ -0,0 +1,5 @@
+-- Support the WHERE clauses built from recommendation criteria.
+CREATE INDEX IX_restaurants_style_filters ON restaurants (style, vegetarian, deliveries)
+  INCLUDE (openHour, closeHour, parking, wifi, wheelchairAccessible);
+
+CREATE INDEX IX_restaurants_hours ON restaurants (openHour, closeHour);
@@ -0,0 +1,4 @@
+-- Correlate logged queries with the X-Request-ID of the request that made them.
+ALTER TABLE query_logs ADD request_id NVARCHAR(128) NULL;
+
+CREATE INDEX IX_query_logs_request_id ON query_logs (request_id);
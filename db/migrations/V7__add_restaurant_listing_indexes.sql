@@ -0,0 +1,6 @@
+-- Support paging through the restaurant listing by keyset on each sort order.
+CREATE INDEX IX_restaurants_name_publicId ON restaurants (name, publicId);
+
+CREATE INDEX IX_restaurants_style_publicId ON restaurants (style, publicId);
+
+CREATE INDEX IX_restaurants_closeHour_publicId ON restaurants (closeHour, publicId);
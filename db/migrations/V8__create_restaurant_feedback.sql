@@ -0,0 +1,12 @@
+-- Store diners' ratings of restaurants, referenced in the API by public ID.
+CREATE TABLE restaurant_feedback (
+  id INT IDENTITY(1,1) PRIMARY KEY,
+  restaurant_id INT NOT NULL
+    CONSTRAINT FK_restaurant_feedback_restaurants REFERENCES restaurants (id) ON DELETE CASCADE,
+  rating TINYINT NOT NULL CONSTRAINT CK_restaurant_feedback_rating CHECK (rating BETWEEN 1 AND 5),
+  comment NVARCHAR(1000) NOT NULL DEFAULT '',
+  request_id NVARCHAR(128) NULL,
+  created_at DATETIME2 NOT NULL DEFAULT SYSUTCDATETIME()
+);
+
+CREATE INDEX IX_restaurant_feedback_restaurant_id ON restaurant_feedback (restaurant_id);
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/kuhlman-labs/restaurant-recommender/config"
+	recommenderv1 "github.com/kuhlman-labs/restaurant-recommender/proto/recommender/v1"
+	restaurantrecommender "github.com/kuhlman-labs/restaurant-recommender/restaurant-recommender"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// newGRPCServer registers the RecommenderService, the standard health service
+// and reflection (for tools like grpcurl) on a gRPC server traced like the
+// HTTP one.
+func newGRPCServer(db *sql.DB, cache *restaurantrecommender.CatalogueCache, logs *restaurantrecommender.QueryLogWriter) (*grpc.Server, *health.Server) {
+	srv := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.UnaryInterceptor(restaurantrecommender.UnaryServerInterceptor),
+	)
+	recommenderv1.RegisterRecommenderServiceServer(srv, restaurantrecommender.NewGRPCServer(db, cache, logs))
+	healthSrv := health.NewServer()
+	healthpb.RegisterHealthServer(srv, healthSrv)
+	reflection.Register(srv)
+	return srv, healthSrv
+}
+
+// serveGRPC runs srv on the configured address until ctx is cancelled, then
+// shuts down like serve: health checks fail for the shutdown delay, and
+// in-flight RPCs get up to the shutdown timeout to finish.
+func serveGRPC(ctx context.Context, srv *grpc.Server, healthSrv *health.Server, cfg config.ServerConfig) error {
+	ln, err := net.Listen("tcp", cfg.GRPCListenAddr)
+	if err != nil {
+		return err
+	}
+	slog.Info("gRPC service is listening", "addr", ln.Addr().String())
+
+	errs := make(chan error, 1)
+	go func() { errs <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	healthSrv.Shutdown()
+	if cfg.ShutdownDelay > 0 {
+		time.Sleep(cfg.ShutdownDelay)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(cfg.ShutdownTimeout):
+		slog.Warn("Timed out draining in-flight RPCs")
+		srv.Stop()
+	}
+	return <-errs
+}
@@ -1,71 +1,54 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
-	"net/http"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/kuhlman-labs/restaurant-recommender/config"
 	restaurantrecommender "github.com/kuhlman-labs/restaurant-recommender/restaurant-recommender"
-	_ "github.com/microsoft/go-mssqldb"
-	"github.com/microsoft/go-mssqldb/azuread"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func main() {
-	var db *sql.DB
-	var err error
-
-	// Retrieve environment variables for DB server and name.
-	server := os.Getenv("DB_SERVER")
-	database := os.Getenv("DB_NAME")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASS")
-
-	if server == "" || database == "" || user == "" || password == "" {
-		log.Fatal("DB_SERVER, DB_NAME, DB_USER, and DB_PASS environment variables must be set.")
-	}
-
-	//connString := fmt.Sprintf("Server=%s;Database=%s;", server, database)
-	connString := fmt.Sprintf("server=%s;user id=%s;password=%s;port=1433;database=%s;fedauth=ActiveDirectoryServicePrincipal", server, user, password, database)
-	/*
-		// Create a managed identity credential.
-		cred, err := azidentity.NewManagedIdentityCredential(nil)
-		if err != nil {
-			log.Fatal("Error creating managed identity credential:", err.Error())
+	cfg, args, err := config.Load(os.Args[1:], os.Getenv)
+	// config print shows the configuration even when it is invalid, to help fix it.
+	if len(args) > 0 && args[0] == "config" {
+		if err := runConfig(cfg, err, args[1:]); err != nil {
+			fatal(err)
 		}
-
-		// Define a token provider function.
-		tokenProvider := func() (string, error) {
-			token, err := cred.GetToken(context.Background(), policy.TokenRequestOptions{
-				Scopes: []string{"https://database.windows.net//.default"},
-			})
-			return token.Token, err
-		}
-
-		// Create the Access Token Connector.
-		connector, err := mssql.NewAccessTokenConnector(connString, tokenProvider)
-		if err != nil {
-			log.Fatal("Connector creation failed:", err.Error())
-		}
-
-		// Open the database connection.
-		db = sql.OpenDB(connector)
-		defer db.Close()
-	*/
-
-	db, err = sql.Open(azuread.DriverName, connString)
+		return
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
 	if err != nil {
-		log.Fatalf("Error opening database connection with connection string %s: %v", connString, err)
+		fatal(fmt.Errorf("invalid configuration:\n%w", err))
 	}
+	slog.SetDefault(slog.New(restaurantrecommender.NewContextHandler(cfg.Logging.Handler(os.Stderr))))
 
+	db, err := openDB(cfg.DB)
+	if err != nil {
+		fatal(err)
+	}
 	defer db.Close()
 
-	// Test the connection.
-	if err := db.Ping(); err != nil {
-		log.Fatalf("Error connecting to database with connection string %s: %v", connString, err)
+	restaurantrecommender.QueryTimeout = cfg.DB.QueryTimeout
+
+	// Subcommands run against the database and exit instead of starting the server.
+	if len(args) > 0 {
+		if err := runCommand(db, args[0], args[1:]); err != nil {
+			db.Close()
+			fatal(err)
+		}
+		return
 	}
-	fmt.Println("Connected to Database...")
 
 	/*
 		//Seeding and creating tables now handled in the flyway migrations.
@@ -81,7 +64,98 @@ func main() {
 		}
 	*/
 
-	http.HandleFunc("/recommend", restaurantrecommender.RecommendHandler(db))
-	fmt.Println("Restaurant recommendation service is running on port :80")
-	log.Fatal(http.ListenAndServe(":80", nil))
+	// Interrupt or SIGTERM (sent by the platform when stopping the container)
+	// starts a graceful shutdown.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := setupTracing(ctx, cfg.Tracing)
+	if err != nil {
+		db.Close()
+		fatal(err)
+	}
+
+	cache := newCatalogueCache(ctx, db, cfg.Cache)
+	logs := newQueryLogWriter(db, cfg)
+
+	if cfg.Features.Metrics {
+		if err := restaurantrecommender.RegisterMetrics(prometheus.DefaultRegisterer, cache, logs); err != nil {
+			db.Close()
+			fatal(err)
+		}
+	}
+
+	readiness := &restaurantrecommender.Readiness{}
+	mux := newMux(db, cache, logs, readiness, cfg)
+	srv := newServer(cfg.Server, newHandler(mux))
+
+	// Both servers shut down when either stops.
+	serveCtx, cancelServe := context.WithCancel(ctx)
+	defer cancelServe()
+	grpcErr := make(chan error, 1)
+	if cfg.Server.GRPCListenAddr != "" {
+		grpcSrv, healthSrv := newGRPCServer(db, cache, logs)
+		go func() {
+			defer cancelServe()
+			grpcErr <- serveGRPC(serveCtx, grpcSrv, healthSrv, cfg.Server)
+		}()
+	} else {
+		slog.Info("gRPC server disabled")
+		grpcErr <- nil
+	}
+	serveErr := serve(serveCtx, srv, readiness, cfg.Server)
+	cancelServe()
+	serveErr = errors.Join(serveErr, <-grpcErr)
+
+	// Requests have drained, so nothing else is queued; write what is pending.
+	flushCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := logs.Close(flushCtx); err != nil {
+		slog.Error("Error flushing query logs", "err", err)
+	}
+	// Flush spans last, so they include the final query-log batches.
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Error("Error flushing traces", "err", err)
+	}
+
+	if serveErr != nil {
+		db.Close()
+		fatal(serveErr)
+	}
+	slog.Info("Restaurant recommendation service stopped")
+}
+
+// newCatalogueCache builds the catalogue cache, or returns nil when its TTL is 0.
+func newCatalogueCache(ctx context.Context, db *sql.DB, cfg config.CacheConfig) *restaurantrecommender.CatalogueCache {
+	if cfg.TTL <= 0 {
+		slog.Info("Catalogue cache disabled")
+		return nil
+	}
+	cache := restaurantrecommender.NewCatalogueCache(db, cfg.TTL)
+	if cfg.RefreshInterval > 0 {
+		cache.StartRefresh(ctx, cfg.RefreshInterval)
+	}
+	return cache
+}
+
+// newQueryLogWriter starts the query-log writer, or returns nil (which
+// discards entries) when query logging is switched off.
+func newQueryLogWriter(db *sql.DB, cfg config.Config) *restaurantrecommender.QueryLogWriter {
+	if !cfg.Features.QueryLog {
+		slog.Info("Query logging disabled")
+		return nil
+	}
+	return restaurantrecommender.NewQueryLogWriter(db, restaurantrecommender.QueryLogConfig{
+		QueueSize:      cfg.QueryLog.QueueSize,
+		BatchSize:      cfg.QueryLog.BatchSize,
+		FlushInterval:  cfg.QueryLog.FlushInterval,
+		EnqueueTimeout: cfg.QueryLog.EnqueueTimeout,
+	})
+}
+
+// fatal logs err and exits. Deferred calls do not run, so callers close the
+// database first.
+func fatal(err error) {
+	slog.Error(err.Error())
+	os.Exit(1)
 }
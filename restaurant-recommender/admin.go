@@ -0,0 +1,236 @@
+package restaurantrecommender
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// maxRequestBody bounds the size of JSON bodies accepted by the admin endpoints.
+const maxRequestBody = 1 << 20
+
+// maxImportBody bounds the size of files uploaded to the import endpoint.
+const maxImportBody = 32 << 20
+
+// writeJSON encodes v as the JSON response body with the given status.
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.ErrorContext(r.Context(), "Error encoding response", "err", err)
+	}
+}
+
+// RequireToken wraps a handler so that it is only served to requests carrying
+// "Authorization: Bearer <token>".
+func RequireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="restaurants"`)
+			writeError(w, r, CodeUnauthorized, "")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// restaurantID parses the {id} path value of the request as a public restaurant ID.
+func restaurantID(r *http.Request) (string, error) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		return "", fmt.Errorf("invalid restaurant id %q", r.PathValue("id"))
+	}
+	return id.String(), nil
+}
+
+// decodeRestaurant decodes a JSON request body onto dst, rejecting unknown fields.
+func decodeRestaurant(w http.ResponseWriter, r *http.Request, dst *Restaurant) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+// writeStoreError maps a data-layer error to a problem response.
+func writeStoreError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, errRestaurantNotFound) {
+		writeError(w, r, CodeRestaurantNotFound, "")
+		return
+	}
+	logStoreError(r.Context(), "Error accessing restaurants", err)
+	writeError(w, r, storeErrorCode(err), "")
+}
+
+// CreateRestaurantHandler returns a handler that adds a restaurant to the catalogue.
+func CreateRestaurantHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var restaurant Restaurant
+		if err := decodeRestaurant(w, r, &restaurant); err != nil {
+			writeError(w, r, CodeInvalidRequest, err.Error())
+			return
+		}
+		if err := validateRestaurant(restaurant); err != nil {
+			writeError(w, r, CodeValidationFailed, err.Error())
+			return
+		}
+		created, err := createRestaurant(r.Context(), db, restaurant)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+		w.Header().Set("Location", "/restaurants/"+created.ID)
+		writeJSON(w, r, http.StatusCreated, created)
+	}
+}
+
+// UpdateRestaurantHandler returns a handler that replaces the restaurant named by {id}.
+func UpdateRestaurantHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := restaurantID(r)
+		if err != nil {
+			writeError(w, r, CodeInvalidRequest, err.Error())
+			return
+		}
+		var restaurant Restaurant
+		if err := decodeRestaurant(w, r, &restaurant); err != nil {
+			writeError(w, r, CodeInvalidRequest, err.Error())
+			return
+		}
+		restaurant.ID = id
+		saveRestaurant(w, r, db, restaurant)
+	}
+}
+
+// PatchRestaurantHandler returns a handler that updates only the fields present
+// in the request body of the restaurant named by {id}.
+func PatchRestaurantHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := restaurantID(r)
+		if err != nil {
+			writeError(w, r, CodeInvalidRequest, err.Error())
+			return
+		}
+		restaurant, err := getRestaurant(r.Context(), db, id)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+		// Decoding onto the stored record leaves absent fields untouched.
+		if err := decodeRestaurant(w, r, &restaurant); err != nil {
+			writeError(w, r, CodeInvalidRequest, err.Error())
+			return
+		}
+		restaurant.ID = id
+		saveRestaurant(w, r, db, restaurant)
+	}
+}
+
+// saveRestaurant validates and stores an updated restaurant, writing the response.
+func saveRestaurant(w http.ResponseWriter, r *http.Request, db *sql.DB, restaurant Restaurant) {
+	if err := validateRestaurant(restaurant); err != nil {
+		writeError(w, r, CodeValidationFailed, err.Error())
+		return
+	}
+	if err := updateRestaurant(r.Context(), db, restaurant); err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, restaurant)
+}
+
+// DeleteRestaurantHandler returns a handler that removes the restaurant named by {id}.
+func DeleteRestaurantHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := restaurantID(r)
+		if err != nil {
+			writeError(w, r, CodeInvalidRequest, err.Error())
+			return
+		}
+		if err := deleteRestaurant(r.Context(), db, id); err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ImportRestaurantsHandler returns a handler that bulk imports restaurants from
+// the request body. The format is taken from the "format" query parameter, or
+// from a text/csv Content-Type, and defaults to JSON lines. Passing dryRun=true
+// validates the upload and reports what would change without writing anything.
+// A row that fails to be stored rolls the whole import back; see ImportReport.
+func ImportRestaurantsHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = ImportFormatJSON
+			if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+				format = ImportFormatCSV
+			}
+		}
+		dryRun, err := strconv.ParseBool(r.URL.Query().Get("dryRun"))
+		if err != nil && r.URL.Query().Get("dryRun") != "" {
+			writeError(w, r, CodeInvalidRequest, "dryRun must be a boolean")
+			return
+		}
+
+		report, err := ImportRestaurants(r.Context(), db, http.MaxBytesReader(w, r.Body, maxImportBody), format, dryRun)
+		if errors.Is(err, errImportStore) {
+			writeStoreError(w, r, err)
+			return
+		}
+		if err != nil {
+			writeError(w, r, CodeInvalidRequest, err.Error())
+			return
+		}
+		writeJSON(w, r, http.StatusOK, report)
+	}
+}
+
+// ExportRestaurantsHandler returns a handler that streams the catalogue as CSV,
+// JSON lines or XML, chosen by the "format" query parameter (default CSV). The
+// optional "columns" parameter is a comma-separated list of fields to include.
+func ExportRestaurantsHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = ExportFormatCSV
+		}
+		var columns []string
+		if c := r.URL.Query().Get("columns"); c != "" {
+			columns = strings.Split(c, ",")
+		}
+		if err := ValidateExport(format, columns); err != nil {
+			writeError(w, r, CodeInvalidRequest, err.Error())
+			return
+		}
+
+		// Headers are only set once the query has opened, so a store failure
+		// can still be reported as a problem.
+		streaming := false
+		started := func() {
+			streaming = true
+			w.Header().Set("Content-Type", ExportContentType(format))
+			w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="restaurants.%s"`, format))
+		}
+		err := exportRestaurants(r.Context(), db, w, format, columns, started)
+		switch {
+		case err != nil && !streaming:
+			writeStoreError(w, r, err)
+		case err != nil:
+			// Once streaming has started the status is already sent, so failures can only be logged.
+			logStoreError(r.Context(), "Error exporting restaurants", err, "format", format)
+		}
+	}
+}
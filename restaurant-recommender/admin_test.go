@@ -0,0 +1,210 @@
+package restaurantrecommender
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// restaurantRowColumns matches selectRestaurantColumns for building mock rows.
+var restaurantRowColumns = []string{
+	"publicId", "name", "style", "address", "openHour", "closeHour", "vegetarian", "deliveries",
+	"phone", "website", "email", "seatingCapacity", "parking", "wifi", "wheelchairAccessible",
+}
+
+// newAdminMux registers the admin handlers on a mux the same way main does,
+// checking every exchange against the OpenAPI document.
+func newAdminMux(t *testing.T) (http.Handler, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /restaurants", CreateRestaurantHandler(db))
+	mux.Handle("PUT /restaurants/{id}", UpdateRestaurantHandler(db))
+	mux.Handle("PATCH /restaurants/{id}", PatchRestaurantHandler(db))
+	mux.Handle("DELETE /restaurants/{id}", DeleteRestaurantHandler(db))
+	return checkContract(t, mux), mock
+}
+
+// decodeError decodes a problem+json error response.
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) Problem {
+	t.Helper()
+	if ct := rec.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Errorf("expected Content-Type %q, got %q", ProblemContentType, ct)
+	}
+	var body Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Error unmarshalling error response %q: %v", rec.Body.String(), err)
+	}
+	return body
+}
+
+// TestRequireToken tests that requests without the configured bearer token are rejected.
+func TestRequireToken(t *testing.T) {
+	handler := RequireToken("secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for _, header := range []string{"", "Bearer wrong", "secret"} {
+		req := httptest.NewRequest(http.MethodGet, "/restaurants", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: expected status 401, got %d", header, rec.Code)
+		}
+		if problem := decodeError(t, rec); problem.Code != CodeUnauthorized {
+			t.Errorf("Authorization %q: expected code %s, got %s", header, CodeUnauthorized, problem.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/restaurants", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204 with a valid token, got %d", rec.Code)
+	}
+}
+
+// TestCreateRestaurantHandler tests that a valid restaurant is inserted and returned with its ID.
+func TestCreateRestaurantHandler(t *testing.T) {
+	mux, mock := newAdminMux(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO restaurants (name, style, address, openHour, closeHour, vegetarian, deliveries, phone, website, email, seatingCapacity, parking, wifi, wheelchairAccessible)")).
+		WithArgs("Curry House", "Indian", "1 Spice Rd", "11:00", "23:30", true, false,
+			"", "", "", 30, false, true, false).
+		WillReturnRows(sqlmock.NewRows([]string{"publicId"}).AddRow("0b9f6c2e-1d3a-4c5b-8e7f-6a5b4c3d2e1f"))
+
+	body := `{"name":"Curry House","style":"Indian","address":"1 Spice Rd","openHour":"11:00","closeHour":"23:30","vegetarian":true,"seatingCapacity":30,"wifi":true}`
+	req := httptest.NewRequest(http.MethodPost, "/restaurants", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if loc := rec.Header().Get("Location"); loc != "/restaurants/0b9f6c2e-1d3a-4c5b-8e7f-6a5b4c3d2e1f" {
+		t.Errorf("Expected Location /restaurants/0b9f6c2e-1d3a-4c5b-8e7f-6a5b4c3d2e1f, got %q", loc)
+	}
+	var created Restaurant
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Error unmarshalling response JSON: %v", err)
+	}
+	if created.ID != "0b9f6c2e-1d3a-4c5b-8e7f-6a5b4c3d2e1f" || created.Name != "Curry House" {
+		t.Errorf("Unexpected created restaurant: %+v", created)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
+
+// TestCreateRestaurantHandler_Invalid tests that invalid bodies are rejected before touching the database.
+func TestCreateRestaurantHandler_Invalid(t *testing.T) {
+	mux, mock := newAdminMux(t)
+
+	tests := []struct {
+		body   string
+		status int
+		want   string
+	}{
+		{`{"name":"X","style":"Thai","openHour":"9am","closeHour":"22:00"}`, http.StatusUnprocessableEntity, "openHour"},
+		{`{"style":"Thai","openHour":"09:00","closeHour":"22:00"}`, http.StatusUnprocessableEntity, "name is required"},
+		{`{"name":"X","style":"Thai","openHour":"09:00","closeHour":"22:00","stars":5}`, http.StatusBadRequest, "unknown field"},
+		{`not json`, http.StatusBadRequest, "invalid request body"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/restaurants", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.body, tt.status, rec.Code)
+		}
+		if problem := decodeError(t, rec); !strings.Contains(problem.Detail, tt.want) {
+			t.Errorf("%s: expected error containing %q, got %q", tt.body, tt.want, problem.Detail)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
+
+// TestPatchRestaurantHandler tests that PATCH only changes the fields present in the body.
+func TestPatchRestaurantHandler(t *testing.T) {
+	mux, mock := newAdminMux(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM restaurants WHERE publicId = @p1")).
+		WithArgs("3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70").
+		WillReturnRows(sqlmock.NewRows(restaurantRowColumns).
+			AddRow("3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70", "Pizza Hut", "Italian", "Wherever Street 99, Somewhere", "09:00", "23:00", true, true,
+				"+1 555 0100", "https://www.pizzahut.com", "info@pizzahut.example", 80, true, true, true))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE restaurants SET")).
+		WithArgs("Pizza Hut", "Italian", "Wherever Street 99, Somewhere", "10:00", "23:00", true, true,
+			"+1 555 0100", "https://www.pizzahut.com", "info@pizzahut.example", 80, true, true, true, "3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	req := httptest.NewRequest(http.MethodPatch, "/restaurants/3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70", strings.NewReader(`{"openHour":"10:00"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var updated Restaurant
+	if err := json.Unmarshal(rec.Body.Bytes(), &updated); err != nil {
+		t.Fatalf("Error unmarshalling response JSON: %v", err)
+	}
+	if updated.OpenHour != "10:00" || updated.Phone != "+1 555 0100" {
+		t.Errorf("Unexpected patched restaurant: %+v", updated)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
+
+// TestDeleteRestaurantHandler tests deleting existing and missing restaurants.
+func TestDeleteRestaurantHandler(t *testing.T) {
+	mux, mock := newAdminMux(t)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM restaurants WHERE publicId = @p1")).
+		WithArgs("3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM restaurants WHERE publicId = @p1")).
+		WithArgs("8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	req := httptest.NewRequest(http.MethodDelete, "/restaurants/3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/restaurants/8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rec.Code)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package restaurantrecommender
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxBatchQueries bounds the number of queries in a batch request.
+const maxBatchQueries = 20
+
+// batchConcurrency bounds the number of queries of a batch answered at once.
+const batchConcurrency = 4
+
+// BatchQuery is one query of a batch, with the v2 result limit.
+type BatchQuery struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+// BatchRequest is the body of a batch recommendation request.
+type BatchRequest struct {
+	Queries []BatchQuery `json:"queries"`
+}
+
+// BatchItem is the answer to one query of a batch: a RecommendationList, or
+// the problem that query had.
+type BatchItem struct {
+	*RecommendationList
+	Error *Problem `json:"error,omitempty"`
+}
+
+// fail records the problem a query of the batch had.
+func (item *BatchItem) fail(r *http.Request, code ErrorCode, detail string) {
+	p := newProblem(r, code, detail)
+	item.Error = &p
+}
+
+// BatchResponse lists the answers in the order of the queries.
+type BatchResponse struct {
+	Items []BatchItem `json:"items"`
+}
+
+// decodeBatch decodes and checks the size of a batch request body.
+func decodeBatch(w http.ResponseWriter, r *http.Request) (BatchRequest, error) {
+	var batch BatchRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&batch); err != nil {
+		return batch, fmt.Errorf("invalid request body: %w", err)
+	}
+	if len(batch.Queries) == 0 || len(batch.Queries) > maxBatchQueries {
+		return batch, fmt.Errorf("queries must have from 1 to %d entries", maxBatchQueries)
+	}
+	return batch, nil
+}
+
+// RecommendBatchHandler returns the /v2/recommend/batch handler. It loads the
+// catalogue once and answers every query of the batch from it concurrently,
+// as /v2/recommend would. A query that fails gets an error item rather than
+// failing the batch; only an invalid body or an unavailable catalogue does.
+func RecommendBatchHandler(db *sql.DB, cache *CatalogueCache, logs *QueryLogWriter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "RecommendBatchHandler",
+			trace.WithAttributes(attribute.String("api.version", "v2")))
+		defer span.End()
+
+		batch, err := decodeBatch(w, r)
+		if err != nil {
+			writeError(w, r, CodeInvalidRequest, err.Error())
+			return
+		}
+		span.SetAttributes(attribute.Int("batch.size", len(batch.Queries)))
+
+		var restaurants []Restaurant
+		if cache != nil {
+			restaurants, err = cache.Restaurants(ctx)
+		} else {
+			restaurants, err = getRestaurants(ctx, db)
+		}
+		if err != nil {
+			logStoreError(ctx, "Error retrieving restaurants", err)
+			span.SetStatus(codes.Error, err.Error())
+			writeError(w, r, storeErrorCode(err), "Error retrieving restaurants")
+			return
+		}
+		snapshot := catalogueSnapshot(restaurants)
+		styles, _ := snapshot.Styles(ctx)
+
+		items := make([]BatchItem, len(batch.Queries))
+		sem := make(chan struct{}, batchConcurrency)
+		var wg sync.WaitGroup
+		for i, q := range batch.Queries {
+			if q.Query == "" {
+				items[i].fail(r, CodeQueryRequired, "")
+				continue
+			}
+			if q.Limit == 0 {
+				q.Limit = defaultRecommendLimit
+			}
+			if q.Limit < 1 || q.Limit > maxRecommendLimit {
+				items[i].fail(r, CodeInvalidRequest, fmt.Sprintf("limit must be from 1 to %d", maxRecommendLimit))
+				continue
+			}
+
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer func() { <-sem; wg.Done() }()
+				ctx, span := tracer.Start(ctx, "RecommendBatchItem", trace.WithAttributes(attribute.Int("batch.index", i)))
+				defer span.End()
+
+				rec, err := answerRecommendation(ctx, db, snapshot, logs, q.Query, parseCriteria(ctx, q.Query, styles))
+				if err != nil {
+					logStoreError(ctx, "Error retrieving restaurants", err)
+					span.SetStatus(codes.Error, err.Error())
+					items[i].fail(r, storeErrorCode(err), "Error retrieving restaurants")
+					return
+				}
+				list := rec.list(q.Limit)
+				items[i].RecommendationList = &list
+			}()
+		}
+		wg.Wait()
+
+		writeJSON(w, r, http.StatusOK, BatchResponse{Items: items})
+	}
+}
@@ -0,0 +1,117 @@
+package restaurantrecommender
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// postBatch sends a batch request body to handler.
+func postBatch(handler http.Handler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/v2/recommend/batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestRecommendBatchHandler tests that every query of a batch is answered
+// from a single load of the catalogue, with errors reported per item.
+func TestRecommendBatchHandler(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	// The catalogue is queried once for the whole batch.
+	expectCatalogueQuery(mock)
+	handler := checkContract(t, RecommendBatchHandler(db, nil, nil))
+
+	rec := postBatch(handler, `{"queries": [
+		{"query": "italian", "limit": 1},
+		{"query": "italian open at 8:45am"},
+		{"query": ""},
+		{"query": "pizza", "limit": 99}
+	]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp BatchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Error unmarshalling response JSON: %v", err)
+	}
+	if len(resp.Items) != 4 {
+		t.Fatalf("Expected 4 items, got %d", len(resp.Items))
+	}
+
+	first := resp.Items[0]
+	if first.Error != nil || first.RecommendationList == nil {
+		t.Fatalf("Expected results for the first query, got %+v", first)
+	}
+	if len(first.Results) != 1 || first.Results[0].Name != "Pizza Hut" || first.Meta.Total != 2 || first.Meta.Query != "italian" {
+		t.Errorf("Unexpected first item %+v", first.RecommendationList)
+	}
+
+	second := resp.Items[1]
+	if second.RecommendationList == nil || len(second.Results) != 0 || !second.Meta.Relaxed ||
+		len(second.Alternatives) != 1 || second.Alternatives[0].Name != "Pizza Hut" {
+		t.Errorf("Expected Pizza Hut as an alternative, got %+v", second)
+	}
+
+	for i, want := range map[int]ErrorCode{2: CodeQueryRequired, 3: CodeInvalidRequest} {
+		item := resp.Items[i]
+		if item.RecommendationList != nil || item.Error == nil || item.Error.Code != want {
+			t.Errorf("Expected item %d to fail with %s, got %+v", i, want, item)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
+
+// TestRecommendBatchHandler_Errors tests the failures that reject a whole batch.
+func TestRecommendBatchHandler_Errors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT .* FROM restaurants").WillReturnError(errors.New("connection refused"))
+	handler := checkContract(t, RecommendBatchHandler(db, nil, nil))
+
+	tooMany := `{"queries": [` + strings.Repeat(`{"query": "pizza"},`, maxBatchQueries) + `{"query": "pizza"}]}`
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		code   ErrorCode
+	}{
+		{"empty", `{"queries": []}`, http.StatusBadRequest, CodeInvalidRequest},
+		{"too many", tooMany, http.StatusBadRequest, CodeInvalidRequest},
+		{"unknown field", `{"query": "pizza"}`, http.StatusBadRequest, CodeInvalidRequest},
+		{"store down", `{"queries": [{"query": "pizza"}]}`, http.StatusServiceUnavailable, CodeStoreUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := postBatch(handler, tt.body)
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+			if problem := decodeError(t, rec); problem.Code != tt.code {
+				t.Errorf("Expected code %s, got %s", tt.code, problem.Code)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
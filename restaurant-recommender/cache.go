@@ -0,0 +1,246 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CatalogueCache keeps the restaurant catalogue and its styles in memory so
+// recommendations can be served without querying the database. Entries expire
+// after the TTL, can be dropped explicitly with Invalidate, and can be kept
+// warm with StartRefresh.
+type CatalogueCache struct {
+	db  *sql.DB
+	ttl time.Duration
+	now func() time.Time
+
+	// loadMu serialises reloads so concurrent misses trigger a single query.
+	loadMu sync.Mutex
+
+	mu          sync.RWMutex
+	restaurants []Restaurant
+	styles      []string
+	loadedAt    time.Time
+	// generation is incremented by Invalidate, so that a load that started
+	// before a write does not store the catalogue as it was before it.
+	generation uint64
+
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	refreshes     atomic.Uint64
+	refreshErrors atomic.Uint64
+}
+
+// CacheStats is a snapshot of the cache counters.
+type CacheStats struct {
+	Hits          uint64  `json:"hits"`
+	Misses        uint64  `json:"misses"`
+	Refreshes     uint64  `json:"refreshes"`
+	RefreshErrors uint64  `json:"refreshErrors"`
+	HitRate       float64 `json:"hitRate"`
+}
+
+// NewCatalogueCache returns an empty cache whose entries live for ttl.
+func NewCatalogueCache(db *sql.DB, ttl time.Duration) *CatalogueCache {
+	return &CatalogueCache{db: db, ttl: ttl, now: time.Now}
+}
+
+// Restaurants returns every restaurant, loading the catalogue if it is missing or expired.
+// The returned slice is shared and must not be modified.
+func (c *CatalogueCache) Restaurants(ctx context.Context) ([]Restaurant, error) {
+	restaurants, _, err := c.get(ctx)
+	return restaurants, err
+}
+
+// Styles returns the distinct restaurant styles, loading the catalogue if it is missing or expired.
+func (c *CatalogueCache) Styles(ctx context.Context) ([]string, error) {
+	_, styles, err := c.get(ctx)
+	return styles, err
+}
+
+// get returns the cached catalogue, reloading it on a miss.
+func (c *CatalogueCache) get(ctx context.Context) ([]Restaurant, []string, error) {
+	if restaurants, styles, ok := c.cached(); ok {
+		c.hits.Add(1)
+		return restaurants, styles, nil
+	}
+	c.misses.Add(1)
+
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+	// Another caller may have reloaded while we waited.
+	if restaurants, styles, ok := c.cached(); ok {
+		return restaurants, styles, nil
+	}
+	return c.load(ctx)
+}
+
+// cached returns the catalogue if it is loaded and fresh.
+func (c *CatalogueCache) cached() ([]Restaurant, []string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.loadedAt.IsZero() || c.now().Sub(c.loadedAt) >= c.ttl {
+		return nil, nil, false
+	}
+	return c.restaurants, c.styles, true
+}
+
+// load reads the catalogue from the database and caches it, unless the cache
+// was invalidated while it was being read. Either way the catalogue read is
+// returned to the caller.
+func (c *CatalogueCache) load(ctx context.Context) ([]Restaurant, []string, error) {
+	c.mu.RLock()
+	generation := c.generation
+	c.mu.RUnlock()
+
+	restaurants, err := getRestaurants(ctx, c.db)
+	c.refreshes.Add(1)
+	if err != nil {
+		c.refreshErrors.Add(1)
+		return nil, nil, err
+	}
+	styles := distinctStyles(restaurants)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == generation {
+		c.restaurants, c.styles, c.loadedAt = restaurants, styles, c.now()
+	}
+	return restaurants, styles, nil
+}
+
+// catalogueSnapshot returns a cache that holds restaurants and never expires,
+// for answering several queries from a single load of the catalogue.
+func catalogueSnapshot(restaurants []Restaurant) *CatalogueCache {
+	return &CatalogueCache{
+		ttl:         time.Duration(math.MaxInt64),
+		now:         time.Now,
+		restaurants: restaurants,
+		styles:      distinctStyles(restaurants),
+		loadedAt:    time.Now(),
+	}
+}
+
+// Refresh reloads the catalogue immediately.
+func (c *CatalogueCache) Refresh(ctx context.Context) error {
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+	_, _, err := c.load(ctx)
+	return err
+}
+
+// Invalidate drops the cached catalogue so the next read reloads it, and
+// discards any load already in progress.
+func (c *CatalogueCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.restaurants, c.styles, c.loadedAt = nil, nil, time.Time{}
+	c.generation++
+}
+
+// StartRefresh reloads the catalogue every interval until ctx is cancelled, so
+// readers keep hitting a warm cache. Failed refreshes are logged and the
+// previous catalogue is kept until it expires.
+func (c *CatalogueCache) StartRefresh(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := c.Refresh(ctx); err != nil {
+					logStoreError(ctx, "Error refreshing restaurant catalogue", err)
+					continue
+				}
+				stats := c.Stats()
+				slog.InfoContext(ctx, "Restaurant catalogue refreshed",
+					"hit_rate", stats.HitRate, "hits", stats.Hits, "misses", stats.Misses)
+			}
+		}
+	}()
+}
+
+// Stats returns the current cache counters.
+func (c *CatalogueCache) Stats() CacheStats {
+	stats := CacheStats{
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Refreshes:     c.refreshes.Load(),
+		RefreshErrors: c.refreshErrors.Load(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
+
+// distinctStyles returns the styles of the restaurants without duplicates,
+// comparing case-insensitively like SELECT DISTINCT under the default collation.
+func distinctStyles(restaurants []Restaurant) []string {
+	seen := make(map[string]bool)
+	var styles []string
+	for _, r := range restaurants {
+		key := strings.ToLower(r.Style)
+		if !seen[key] {
+			seen[key] = true
+			styles = append(styles, r.Style)
+		}
+	}
+	return styles
+}
+
+// InvalidateOnWrite wraps a handler that modifies the catalogue so that the
+// cache is invalidated after every successful request. Dry runs
+// (dryRun=true) write nothing, so they leave the cache alone.
+func InvalidateOnWrite(cache *CatalogueCache, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		if dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dryRun")); dryRun {
+			return
+		}
+		if cache != nil && rec.status < http.StatusBadRequest {
+			cache.Invalidate()
+		}
+	})
+}
+
+// catalogueStyles returns the restaurant styles from the cache when one is configured.
+func catalogueStyles(ctx context.Context, db *sql.DB, cache *CatalogueCache) ([]string, error) {
+	if cache != nil {
+		return cache.Styles(ctx)
+	}
+	return getRestaurantStyles(ctx, db)
+}
+
+// matchingRestaurants returns the restaurants matching the criteria. Without a
+// cache they are filtered in the database by findRestaurants, which is the
+// authoritative path. With a cache the cached catalogue is filtered in memory
+// with restaurantMatchesCriteria, which must agree with buildRestaurantQuery,
+// and may be up to a TTL behind writes made outside this instance.
+func matchingRestaurants(ctx context.Context, db *sql.DB, cache *CatalogueCache, criteria QueryCriteria, now time.Time) ([]Restaurant, error) {
+	if cache == nil {
+		return findRestaurants(ctx, db, criteria, now)
+	}
+	restaurants, err := cache.Restaurants(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var matches []Restaurant
+	for _, r := range restaurants {
+		if restaurantMatchesCriteria(r, criteria, now) {
+			matches = append(matches, r)
+		}
+	}
+	return matches, nil
+}
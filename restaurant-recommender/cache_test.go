@@ -0,0 +1,182 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// expectCatalogueQuery expects one full catalogue load returning two restaurants.
+func expectCatalogueQuery(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT " + selectRestaurantColumns + " FROM restaurants")).
+		WillReturnRows(sqlmock.NewRows(restaurantRowColumns).
+			AddRow("3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70", "Pizza Hut", "Italian", "Wherever Street 99, Somewhere", "09:00", "23:00", true, true,
+				"", "", "", 80, true, true, true).
+			AddRow("5d6e7f80-9a0b-4c1d-8e2f-3a4b5c6d7e8f", "Luigi's", "italian", "1 Pasta Lane", "12:00", "22:00", false, false,
+				"", "", "", 20, false, false, false))
+}
+
+// TestCatalogueCache tests hits, TTL expiry and explicit invalidation.
+func TestCatalogueCache(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	clock := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	cache := NewCatalogueCache(db, time.Minute)
+	cache.now = func() time.Time { return clock }
+
+	expectCatalogueQuery(mock)
+	styles, err := cache.Styles(context.Background())
+	if err != nil {
+		t.Fatalf("Styles returned error: %v", err)
+	}
+	if len(styles) != 1 || styles[0] != "Italian" {
+		t.Errorf("expected styles to be deduplicated case-insensitively, got %v", styles)
+	}
+	restaurants, err := cache.Restaurants(context.Background())
+	if err != nil || len(restaurants) != 2 {
+		t.Fatalf("expected 2 cached restaurants, got %d (err %v)", len(restaurants), err)
+	}
+
+	// Still fresh: no further queries.
+	clock = clock.Add(59 * time.Second)
+	if _, err := cache.Restaurants(context.Background()); err != nil {
+		t.Errorf("Restaurants returned error: %v", err)
+	}
+
+	// Expired: reloads once.
+	clock = clock.Add(time.Second)
+	expectCatalogueQuery(mock)
+	if _, err := cache.Restaurants(context.Background()); err != nil {
+		t.Errorf("Restaurants returned error: %v", err)
+	}
+
+	// Invalidated: reloads once more.
+	cache.Invalidate()
+	expectCatalogueQuery(mock)
+	if _, err := cache.Styles(context.Background()); err != nil {
+		t.Errorf("Styles returned error: %v", err)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 3 || stats.Refreshes != 3 || stats.HitRate != 0.4 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// TestCatalogueCache_InvalidateDuringLoad tests that a load overtaken by an
+// invalidation answers its caller but is not cached.
+func TestCatalogueCache_InvalidateDuringLoad(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	// The load reads the catalogue as it was before the write below.
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT " + selectRestaurantColumns + " FROM restaurants")).
+		WillDelayFor(200 * time.Millisecond).
+		WillReturnRows(sqlmock.NewRows(restaurantRowColumns).
+			AddRow("3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70", "Pizza Hut", "Italian", "Wherever Street 99", "09:00", "23:00", true, true,
+				"", "", "", 80, true, true, true))
+
+	cache := NewCatalogueCache(db, time.Minute)
+	loaded := make(chan []Restaurant)
+	go func() {
+		restaurants, err := cache.Restaurants(context.Background())
+		if err != nil {
+			t.Errorf("Restaurants returned error: %v", err)
+		}
+		loaded <- restaurants
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cache.Invalidate()
+	if restaurants := <-loaded; len(restaurants) != 1 {
+		t.Errorf("expected the load to answer its caller, got %v", restaurants)
+	}
+	if _, _, ok := cache.cached(); ok {
+		t.Error("expected a load overtaken by Invalidate not to be cached")
+	}
+
+	// The next read loads the catalogue as it is after the write.
+	expectCatalogueQuery(mock)
+	if restaurants, err := cache.Restaurants(context.Background()); err != nil || len(restaurants) != 2 {
+		t.Errorf("expected a fresh load of 2 restaurants, got %d (err %v)", len(restaurants), err)
+	}
+	if _, _, ok := cache.cached(); !ok {
+		t.Error("expected the fresh load to be cached")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// TestCatalogueCache_RefreshError tests that a failed load is reported and counted.
+func TestCatalogueCache_RefreshError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM restaurants")).WillReturnError(errors.New("connection reset"))
+
+	cache := NewCatalogueCache(db, time.Minute)
+	if _, err := cache.Restaurants(context.Background()); err == nil {
+		t.Error("expected an error from a failed load")
+	}
+	if stats := cache.Stats(); stats.RefreshErrors != 1 {
+		t.Errorf("expected one refresh error, got %+v", stats)
+	}
+}
+
+// TestInvalidateOnWrite tests that only successful writes, and not dry runs,
+// invalidate the cache.
+func TestInvalidateOnWrite(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	cache := NewCatalogueCache(db, time.Hour)
+	expectCatalogueQuery(mock)
+	if err := cache.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh returned error: %v", err)
+	}
+
+	failing := InvalidateOnWrite(cache, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, CodeValidationFailed, "invalid")
+	}))
+	failing.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/restaurants", nil))
+	if _, _, ok := cache.cached(); !ok {
+		t.Error("expected a failed write to keep the cache")
+	}
+
+	succeeding := InvalidateOnWrite(cache, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	succeeding.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/restaurants/import?dryRun=true", nil))
+	if _, _, ok := cache.cached(); !ok {
+		t.Error("expected a dry run to keep the cache")
+	}
+	succeeding.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/restaurants/x", nil))
+	if _, _, ok := cache.cached(); ok {
+		t.Error("expected a successful write to invalidate the cache")
+	}
+}
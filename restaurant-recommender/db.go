@@ -1,26 +1,37 @@
 package restaurantrecommender
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
-// Updated createTables creates the restaurants and query_logs tables for Azure SQL.
+// Updated createTables creates the restaurants, query_logs and restaurant_feedback tables for Azure SQL.
 func CreateTables(db *sql.DB) error {
 	restaurantTable := `
     IF NOT EXISTS (SELECT * FROM sys.tables WHERE name = 'restaurants')
     BEGIN
       CREATE TABLE restaurants (
         id INT IDENTITY(1,1) PRIMARY KEY,
+        publicId UNIQUEIDENTIFIER NOT NULL DEFAULT NEWID() UNIQUE,
         name NVARCHAR(255) NOT NULL,
         style NVARCHAR(255) NOT NULL,
         address NVARCHAR(255),
         openHour NVARCHAR(5) NOT NULL,
         closeHour NVARCHAR(5) NOT NULL,
         vegetarian BIT,
-        deliveries BIT
+        deliveries BIT,
+        phone NVARCHAR(50) NOT NULL DEFAULT '',
+        website NVARCHAR(255) NOT NULL DEFAULT '',
+        email NVARCHAR(255) NOT NULL DEFAULT '',
+        seatingCapacity INT NOT NULL DEFAULT 0,
+        parking BIT NOT NULL DEFAULT 0,
+        wifi BIT NOT NULL DEFAULT 0,
+        wheelchairAccessible BIT NOT NULL DEFAULT 0
       );
     END;`
 	_, err := db.Exec(restaurantTable)
@@ -35,10 +46,28 @@ func CreateTables(db *sql.DB) error {
         id INT IDENTITY(1,1) PRIMARY KEY,
         query NVARCHAR(MAX) NOT NULL,
         response NVARCHAR(MAX) NOT NULL,
-        created_at DATETIME DEFAULT GETDATE()
+        created_at DATETIME DEFAULT GETDATE(),
+        request_id NVARCHAR(128) NULL
       );
     END;`
 	_, err = db.Exec(queryLogsTable)
+	if err != nil {
+		return err
+	}
+
+	feedbackTable := `
+    IF NOT EXISTS (SELECT * FROM sys.tables WHERE name = 'restaurant_feedback')
+    BEGIN
+      CREATE TABLE restaurant_feedback (
+        id INT IDENTITY(1,1) PRIMARY KEY,
+        restaurant_id INT NOT NULL REFERENCES restaurants (id) ON DELETE CASCADE,
+        rating TINYINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+        comment NVARCHAR(1000) NOT NULL DEFAULT '',
+        request_id NVARCHAR(128) NULL,
+        created_at DATETIME2 NOT NULL DEFAULT SYSUTCDATETIME()
+      );
+    END;`
+	_, err = db.Exec(feedbackTable)
 	return err
 }
 
@@ -55,45 +84,60 @@ func SeedRestaurants(db *sql.DB) error {
 
 	sampleRestaurants := []Restaurant{
 		{
-			Name:       "Pizza Hut",
-			Style:      "Italian",
-			Address:    "Wherever Street 99, Somewhere",
-			OpenHour:   "09:00",
-			CloseHour:  "23:00",
-			Vegetarian: true,
-			Deliveries: true,
+			Name:                 "Pizza Hut",
+			Style:                "Italian",
+			Address:              "Wherever Street 99, Somewhere",
+			OpenHour:             "09:00",
+			CloseHour:            "23:00",
+			Vegetarian:           true,
+			Deliveries:           true,
+			Phone:                "+1 555 0100",
+			Website:              "https://www.pizzahut.com",
+			Email:                "info@pizzahut.example",
+			SeatingCapacity:      80,
+			Parking:              true,
+			WiFi:                 true,
+			WheelchairAccessible: true,
 		},
 		{
-			Name:       "Taco Bell",
-			Style:      "Mexican",
-			Address:    "123 Burrito Blvd, Somecity",
-			OpenHour:   "10:00",
-			CloseHour:  "22:00",
-			Vegetarian: false,
-			Deliveries: true,
+			Name:                 "Taco Bell",
+			Style:                "Mexican",
+			Address:              "123 Burrito Blvd, Somecity",
+			OpenHour:             "10:00",
+			CloseHour:            "22:00",
+			Vegetarian:           false,
+			Deliveries:           true,
+			Phone:                "+1 555 0101",
+			Website:              "https://www.tacobell.com",
+			Email:                "info@tacobell.example",
+			SeatingCapacity:      40,
+			Parking:              true,
+			WiFi:                 false,
+			WheelchairAccessible: true,
 		},
 		{
-			Name:       "Seoul Bites",
-			Style:      "Korean",
-			Address:    "123 Kimchi Ave, Seoul",
-			OpenHour:   "11:00",
-			CloseHour:  "22:00",
-			Vegetarian: false,
-			Deliveries: false,
+			Name:                 "Seoul Bites",
+			Style:                "Korean",
+			Address:              "123 Kimchi Ave, Seoul",
+			OpenHour:             "11:00",
+			CloseHour:            "22:00",
+			Vegetarian:           false,
+			Deliveries:           false,
+			Phone:                "+82 2 555 0102",
+			Website:              "",
+			Email:                "hello@seoulbites.example",
+			SeatingCapacity:      24,
+			Parking:              false,
+			WiFi:                 true,
+			WheelchairAccessible: false,
 		},
 	}
 
 	for _, r := range sampleRestaurants {
 		_, err := db.Exec(
-			`INSERT INTO restaurants (name, style, address, openHour, closeHour, vegetarian, deliveries)
-            VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7)`,
-			sql.Named("p1", r.Name),
-			sql.Named("p2", r.Style),
-			sql.Named("p3", r.Address),
-			sql.Named("p4", r.OpenHour),
-			sql.Named("p5", r.CloseHour),
-			sql.Named("p6", r.Vegetarian),
-			sql.Named("p7", r.Deliveries),
+			`INSERT INTO restaurants (`+restaurantColumns+`)
+            VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, @p10, @p11, @p12, @p13, @p14)`,
+			restaurantArgs(r)...,
 		)
 		if err != nil {
 			return err
@@ -102,9 +146,39 @@ func SeedRestaurants(db *sql.DB) error {
 	return nil
 }
 
+// QueryTimeout bounds each database call made on behalf of a request. It may be
+// changed at startup; zero leaves calls bounded only by the caller's context.
+var QueryTimeout = 5 * time.Second
+
+// withQueryTimeout derives the context for a single database call, made by the
+// named data-layer function, from ctx. The returned finish function must be
+// deferred with the call's error: it releases the context, records the call's
+// duration and span and, if the call failed because the context ended, makes
+// the error wrap the context's error so callers can tell timeouts and
+// cancellations from other failures.
+func withQueryTimeout(ctx context.Context, function string) (context.Context, func(*error)) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, function, trace.WithSpanKind(trace.SpanKindClient))
+	cancel := context.CancelFunc(func() {})
+	if QueryTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, QueryTimeout)
+	}
+	return ctx, func(err *error) {
+		if *err != nil && ctx.Err() != nil && !errors.Is(*err, ctx.Err()) {
+			*err = fmt.Errorf("%w: %v", ctx.Err(), *err)
+		}
+		observeQuery(function, start, *err)
+		endSpan(span, *err)
+		cancel()
+	}
+}
+
 // getRestaurantStyles retrieves distinct restaurant styles from the database.
-func getRestaurantStyles(db *sql.DB) ([]string, error) {
-	rows, err := db.Query("SELECT DISTINCT style FROM restaurants")
+func getRestaurantStyles(ctx context.Context, db *sql.DB) (_ []string, err error) {
+	ctx, finish := withQueryTimeout(ctx, "getRestaurantStyles")
+	defer finish(&err)
+
+	rows, err := db.QueryContext(ctx, "SELECT DISTINCT style FROM restaurants")
 	if err != nil {
 		return nil, err
 	}
@@ -118,12 +192,87 @@ func getRestaurantStyles(db *sql.DB) ([]string, error) {
 		}
 		styles = append(styles, style)
 	}
-	return styles, nil
+	return styles, rows.Err()
+}
+
+// errRestaurantNotFound is returned when no restaurant exists with the requested ID.
+var errRestaurantNotFound = errors.New("restaurant not found")
+
+// restaurantColumns lists the writable restaurant columns in the order bound by restaurantArgs.
+const restaurantColumns = "name, style, address, openHour, closeHour, vegetarian, deliveries, " +
+	"phone, website, email, seatingCapacity, parking, wifi, wheelchairAccessible"
+
+// publicIDColumn selects the public identifier as a lower-case UUID string.
+const publicIDColumn = "LOWER(CONVERT(NVARCHAR(36), publicId))"
+
+// selectRestaurantColumns lists the columns in the order scanned by scanRestaurant.
+const selectRestaurantColumns = publicIDColumn + ", " + restaurantColumns
+
+// restaurantArgs returns the named parameters @p1..@p14 matching restaurantColumns.
+func restaurantArgs(r Restaurant) []any {
+	return []any{
+		sql.Named("p1", r.Name),
+		sql.Named("p2", r.Style),
+		sql.Named("p3", r.Address),
+		sql.Named("p4", r.OpenHour),
+		sql.Named("p5", r.CloseHour),
+		sql.Named("p6", r.Vegetarian),
+		sql.Named("p7", r.Deliveries),
+		sql.Named("p8", r.Phone),
+		sql.Named("p9", r.Website),
+		sql.Named("p10", r.Email),
+		sql.Named("p11", r.SeatingCapacity),
+		sql.Named("p12", r.Parking),
+		sql.Named("p13", r.WiFi),
+		sql.Named("p14", r.WheelchairAccessible),
+	}
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanRestaurant scans a row selected with selectRestaurantColumns into a Restaurant.
+func scanRestaurant(row rowScanner) (Restaurant, error) {
+	var r Restaurant
+	err := row.Scan(
+		&r.ID, &r.Name, &r.Style, &r.Address, &r.OpenHour, &r.CloseHour, &r.Vegetarian, &r.Deliveries,
+		&r.Phone, &r.Website, &r.Email, &r.SeatingCapacity, &r.Parking, &r.WiFi, &r.WheelchairAccessible,
+	)
+	return r, err
 }
 
 // getRestaurants retrieves all restaurant records.
-func getRestaurants(db *sql.DB) ([]Restaurant, error) {
-	rows, err := db.Query("SELECT name, style, address, openHour, closeHour, vegetarian, deliveries FROM restaurants")
+func getRestaurants(ctx context.Context, db *sql.DB) (_ []Restaurant, err error) {
+	ctx, finish := withQueryTimeout(ctx, "getRestaurants")
+	defer finish(&err)
+
+	rows, err := db.QueryContext(ctx, "SELECT "+selectRestaurantColumns+" FROM restaurants")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var restaurants []Restaurant
+	err = scanRestaurants(rows, func(r Restaurant) error {
+		restaurants = append(restaurants, r)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restaurants, nil
+}
+
+// findRestaurants retrieves the restaurants matching the criteria at the given
+// time, filtering in the database rather than in Go.
+func findRestaurants(ctx context.Context, db *sql.DB, criteria QueryCriteria, now time.Time) (_ []Restaurant, err error) {
+	ctx, finish := withQueryTimeout(ctx, "findRestaurants")
+	defer finish(&err)
+
+	query, args := buildRestaurantQuery(criteria, now)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -131,30 +280,181 @@ func getRestaurants(db *sql.DB) ([]Restaurant, error) {
 
 	var restaurants []Restaurant
 	for rows.Next() {
-		var r Restaurant
-		if err := rows.Scan(&r.Name, &r.Style, &r.Address, &r.OpenHour, &r.CloseHour, &r.Vegetarian, &r.Deliveries); err != nil {
+		r, err := scanRestaurant(rows)
+		if err != nil {
 			return nil, err
 		}
 		restaurants = append(restaurants, r)
 	}
-	return restaurants, nil
+	return restaurants, rows.Err()
 }
 
-// logQueryAndResponse inserts the query and JSON response into the query_logs table.
-func logQueryAndResponse(query string, response Recommendation, db *sql.DB) {
-	responseJSON, err := json.Marshal(response)
+// listRestaurants retrieves a page of the restaurant listing. next is the
+// cursor for the following page, or nil on the last page.
+func listRestaurants(ctx context.Context, db *sql.DB, opts listOptions, now time.Time) (_ []Restaurant, next *restaurantCursor, err error) {
+	ctx, finish := withQueryTimeout(ctx, "listRestaurants")
+	defer finish(&err)
+
+	// Reading one row more than the page tells whether another page follows.
+	limit := opts.limit
+	opts.limit++
+	query, args := buildListQuery(opts, now)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
-		log.Printf("Error marshalling response: %v", err)
-		return
+		return nil, nil, err
 	}
+	defer rows.Close()
 
-	_, err = db.Exec(
-		"INSERT INTO query_logs (query, response, created_at) VALUES (@p1, @p2, @p3)",
-		sql.Named("p1", query),
-		sql.Named("p2", string(responseJSON)),
-		sql.Named("p3", time.Now()),
+	restaurants := []Restaurant{}
+	for rows.Next() {
+		r, err := scanRestaurant(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		restaurants = append(restaurants, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(restaurants) > limit {
+		restaurants = restaurants[:limit]
+		next = newRestaurantCursor(restaurants[limit-1], opts.sort, opts.desc)
+	}
+	return restaurants, next, nil
+}
+
+// forEachRestaurant streams every restaurant record to fn without loading them
+// all into memory, stopping at the first error fn returns. opened, if not nil,
+// is called once the query has succeeded and before the first record, so that
+// callers can hold back output until they know the query runs. It applies no
+// deadline of its own, since streaming may outlast QueryTimeout.
+func forEachRestaurant(ctx context.Context, db *sql.DB, opened func() error, fn func(Restaurant) error) (err error) {
+	ctx, span := tracer.Start(ctx, "forEachRestaurant", trace.WithSpanKind(trace.SpanKindClient))
+	defer func(start time.Time) {
+		observeQuery("forEachRestaurant", start, err)
+		endSpan(span, err)
+	}(time.Now())
+
+	rows, err := db.QueryContext(ctx, "SELECT "+selectRestaurantColumns+" FROM restaurants")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if opened != nil {
+		if err := opened(); err != nil {
+			return err
+		}
+	}
+	return scanRestaurants(rows, fn)
+}
+
+// scanRestaurants passes each restaurant record in rows to fn, stopping at
+// the first error fn returns. It is not instrumented itself, since its callers
+// already time the query the rows came from.
+func scanRestaurants(rows *sql.Rows, fn func(Restaurant) error) error {
+	for rows.Next() {
+		r, err := scanRestaurant(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx, so that writes can be
+// grouped into a transaction.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// getRestaurant retrieves a single restaurant by its public ID.
+func getRestaurant(ctx context.Context, db *sql.DB, id string) (_ Restaurant, err error) {
+	ctx, finish := withQueryTimeout(ctx, "getRestaurant")
+	defer finish(&err)
+
+	row := db.QueryRowContext(ctx, "SELECT "+selectRestaurantColumns+" FROM restaurants WHERE publicId = @p1", sql.Named("p1", id))
+	r, err := scanRestaurant(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Restaurant{}, errRestaurantNotFound
+	}
+	return r, err
+}
+
+// findRestaurantID returns the public ID of the restaurant with the given name and address.
+func findRestaurantID(ctx context.Context, db queryer, name, address string) (_ string, err error) {
+	ctx, finish := withQueryTimeout(ctx, "findRestaurantID")
+	defer finish(&err)
+
+	var id string
+	err = db.QueryRowContext(ctx,
+		"SELECT "+publicIDColumn+" FROM restaurants WHERE name = @p1 AND address = @p2",
+		sql.Named("p1", name),
+		sql.Named("p2", address),
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", errRestaurantNotFound
+	}
+	return id, err
+}
+
+// createRestaurant inserts a restaurant and returns it with its generated public ID.
+func createRestaurant(ctx context.Context, db queryer, r Restaurant) (_ Restaurant, err error) {
+	ctx, finish := withQueryTimeout(ctx, "createRestaurant")
+	defer finish(&err)
+
+	err = db.QueryRowContext(ctx,
+		`INSERT INTO restaurants (`+restaurantColumns+`)
+        OUTPUT LOWER(CONVERT(NVARCHAR(36), INSERTED.publicId))
+        VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, @p10, @p11, @p12, @p13, @p14)`,
+		restaurantArgs(r)...,
+	).Scan(&r.ID)
+	return r, err
+}
+
+// updateRestaurant replaces every writable column of the restaurant with r.ID.
+func updateRestaurant(ctx context.Context, db queryer, r Restaurant) (err error) {
+	ctx, finish := withQueryTimeout(ctx, "updateRestaurant")
+	defer finish(&err)
+
+	args := append(restaurantArgs(r), sql.Named("p15", r.ID))
+	res, err := db.ExecContext(ctx,
+		`UPDATE restaurants SET name = @p1, style = @p2, address = @p3, openHour = @p4, closeHour = @p5,
+        vegetarian = @p6, deliveries = @p7, phone = @p8, website = @p9, email = @p10,
+        seatingCapacity = @p11, parking = @p12, wifi = @p13, wheelchairAccessible = @p14
+        WHERE publicId = @p15`,
+		args...,
 	)
 	if err != nil {
-		log.Printf("Error logging query and response: %v", err)
+		return err
+	}
+	return requireAffected(res)
+}
+
+// deleteRestaurant removes the restaurant with the given public ID.
+func deleteRestaurant(ctx context.Context, db *sql.DB, id string) (err error) {
+	ctx, finish := withQueryTimeout(ctx, "deleteRestaurant")
+	defer finish(&err)
+
+	res, err := db.ExecContext(ctx, "DELETE FROM restaurants WHERE publicId = @p1", sql.Named("p1", id))
+	if err != nil {
+		return err
 	}
+	return requireAffected(res)
+}
+
+// requireAffected maps a statement that touched no rows to errRestaurantNotFound.
+func requireAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errRestaurantNotFound
+	}
+	return nil
 }
@@ -1,8 +1,11 @@
 package restaurantrecommender
 
 import (
+	"context"
+	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 )
@@ -21,6 +24,9 @@ func TestCreateTables(t *testing.T) {
 	// Expect the Exec for creating the query_logs table.
 	mock.ExpectExec(regexp.QuoteMeta("IF NOT EXISTS (SELECT * FROM sys.tables WHERE name = 'query_logs')")).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	// Expect the Exec for creating the restaurant_feedback table.
+	mock.ExpectExec(regexp.QuoteMeta("IF NOT EXISTS (SELECT * FROM sys.tables WHERE name = 'restaurant_feedback')")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	if err := CreateTables(db); err != nil {
 		t.Errorf("CreateTables returned error: %v", err)
@@ -45,14 +51,17 @@ func TestSeedRestaurants_NoData(t *testing.T) {
 		WillReturnRows(countRows)
 
 	// There are three sample restaurants so expect three INSERT statements.
-	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO restaurants (name, style, address, openHour, closeHour, vegetarian, deliveries)")).
-		WithArgs("Pizza Hut", "Italian", "Wherever Street 99, Somewhere", "09:00", "23:00", true, true).
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO restaurants (name, style, address, openHour, closeHour, vegetarian, deliveries, phone, website, email, seatingCapacity, parking, wifi, wheelchairAccessible)")).
+		WithArgs("Pizza Hut", "Italian", "Wherever Street 99, Somewhere", "09:00", "23:00", true, true,
+			"+1 555 0100", "https://www.pizzahut.com", "info@pizzahut.example", 80, true, true, true).
 		WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO restaurants (name, style, address, openHour, closeHour, vegetarian, deliveries)")).
-		WithArgs("Taco Bell", "Mexican", "123 Burrito Blvd, Somecity", "10:00", "22:00", false, true).
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO restaurants (name, style, address, openHour, closeHour, vegetarian, deliveries, phone, website, email, seatingCapacity, parking, wifi, wheelchairAccessible)")).
+		WithArgs("Taco Bell", "Mexican", "123 Burrito Blvd, Somecity", "10:00", "22:00", false, true,
+			"+1 555 0101", "https://www.tacobell.com", "info@tacobell.example", 40, true, false, true).
 		WillReturnResult(sqlmock.NewResult(2, 1))
-	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO restaurants (name, style, address, openHour, closeHour, vegetarian, deliveries)")).
-		WithArgs("Seoul Bites", "Korean", "123 Kimchi Ave, Seoul", "11:00", "22:00", false, false).
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO restaurants (name, style, address, openHour, closeHour, vegetarian, deliveries, phone, website, email, seatingCapacity, parking, wifi, wheelchairAccessible)")).
+		WithArgs("Seoul Bites", "Korean", "123 Kimchi Ave, Seoul", "11:00", "22:00", false, false,
+			"+82 2 555 0102", "", "hello@seoulbites.example", 24, false, true, false).
 		WillReturnResult(sqlmock.NewResult(3, 1))
 
 	if err := SeedRestaurants(db); err != nil {
@@ -105,7 +114,7 @@ func TestGetRestaurantStyles(t *testing.T) {
 	mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT style FROM restaurants")).
 		WillReturnRows(rows)
 
-	gotStyles, err := getRestaurantStyles(db)
+	gotStyles, err := getRestaurantStyles(context.Background(), db)
 	if err != nil {
 		t.Errorf("getRestaurantStyles returned error: %v", err)
 	}
@@ -127,20 +136,26 @@ func TestGetRestaurants(t *testing.T) {
 	defer db.Close()
 
 	restaurantRows := sqlmock.NewRows([]string{
-		"name", "style", "address", "openHour", "closeHour", "vegetarian", "deliveries",
+		"id", "name", "style", "address", "openHour", "closeHour", "vegetarian", "deliveries",
+		"phone", "website", "email", "seatingCapacity", "parking", "wifi", "wheelchairAccessible",
 	}).
-		AddRow("Pizza Hut", "Italian", "Wherever Street 99, Somewhere", "09:00", "23:00", true, true).
-		AddRow("Taco Bell", "Mexican", "123 Burrito Blvd, Somecity", "10:00", "22:00", false, true)
+		AddRow("3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70", "Pizza Hut", "Italian", "Wherever Street 99, Somewhere", "09:00", "23:00", true, true,
+			"+1 555 0100", "https://www.pizzahut.com", "info@pizzahut.example", 80, true, true, true).
+		AddRow("8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d", "Taco Bell", "Mexican", "123 Burrito Blvd, Somecity", "10:00", "22:00", false, true,
+			"+1 555 0101", "https://www.tacobell.com", "info@tacobell.example", 40, true, false, true)
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT name, style, address, openHour, closeHour, vegetarian, deliveries FROM restaurants")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT LOWER(CONVERT(NVARCHAR(36), publicId)), name, style, address, openHour, closeHour, vegetarian, deliveries, phone, website, email, seatingCapacity, parking, wifi, wheelchairAccessible FROM restaurants")).
 		WillReturnRows(restaurantRows)
 
-	restaurants, err := getRestaurants(db)
+	restaurants, err := getRestaurants(context.Background(), db)
 	if err != nil {
 		t.Errorf("getRestaurants returned error: %v", err)
 	}
 	if len(restaurants) != 2 {
-		t.Errorf("expected 2 restaurants, got %d", len(restaurants))
+		t.Fatalf("expected 2 restaurants, got %d", len(restaurants))
+	}
+	if restaurants[0].Phone != "+1 555 0100" || restaurants[0].SeatingCapacity != 80 || !restaurants[0].WiFi {
+		t.Errorf("contact and amenity fields not scanned: %+v", restaurants[0])
 	}
 
 	if err := mock.ExpectationsWereMet(); err != nil {
@@ -148,37 +163,47 @@ func TestGetRestaurants(t *testing.T) {
 	}
 }
 
-func TestLogQueryAndResponse(t *testing.T) {
+// TestQueryTimeout tests that a slow query is abandoned after QueryTimeout and
+// reported as a deadline error.
+func TestQueryTimeout(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("failed to open sqlmock DB: %v", err)
 	}
 	defer db.Close()
 
-	resp := Recommendation{
-		RestaurantRecommendation: Restaurant{
-			Name:       "Pizza Hut",
-			Style:      "Italian",
-			Address:    "Wherever Street 99, Somewhere",
-			OpenHour:   "09:00",
-			CloseHour:  "23:00",
-			Vegetarian: true,
-			Deliveries: true,
-		},
-	}
-
-	// Expect the Exec with the appropriate arguments.
-	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO query_logs (query, response, created_at) VALUES (@p1, @p2, @p3)")).
-		WithArgs(
-			"test query",
-			sqlmock.AnyArg(), // response JSON (can't predict exact value)
-			sqlmock.AnyArg(), // current time (any value)
-		).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	defer func(timeout time.Duration) { QueryTimeout = timeout }(QueryTimeout)
+	QueryTimeout = 10 * time.Millisecond
 
-	logQueryAndResponse("test query", resp, db)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT style FROM restaurants")).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"style"}).AddRow("Italian"))
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("unmet expectations: %v", err)
+	start := time.Now()
+	_, err = getRestaurantStyles(context.Background(), db)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected the query to be abandoned promptly, took %v", elapsed)
+	}
+}
+
+// TestQueryCancelled tests that cancelling the caller's context cancels the query.
+func TestQueryCancelled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM restaurants")).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows(restaurantRowColumns))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := getRestaurants(ctx, db); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancellation error, got %v", err)
 	}
 }
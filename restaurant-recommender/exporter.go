@@ -0,0 +1,242 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Supported catalogue export formats.
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatJSON = "json"
+	ExportFormatXML  = "xml"
+)
+
+// exportField is a named Restaurant field that can be selected for export.
+type exportField struct {
+	name  string
+	value func(r Restaurant) any
+}
+
+// exportFields lists the exportable fields, named as in the Restaurant JSON, in output order.
+var exportFields = []exportField{
+	{"id", func(r Restaurant) any { return r.ID }},
+	{"name", func(r Restaurant) any { return r.Name }},
+	{"style", func(r Restaurant) any { return r.Style }},
+	{"address", func(r Restaurant) any { return r.Address }},
+	{"openHour", func(r Restaurant) any { return r.OpenHour }},
+	{"closeHour", func(r Restaurant) any { return r.CloseHour }},
+	{"vegetarian", func(r Restaurant) any { return r.Vegetarian }},
+	{"deliveries", func(r Restaurant) any { return r.Deliveries }},
+	{"phone", func(r Restaurant) any { return r.Phone }},
+	{"website", func(r Restaurant) any { return r.Website }},
+	{"email", func(r Restaurant) any { return r.Email }},
+	{"seatingCapacity", func(r Restaurant) any { return r.SeatingCapacity }},
+	{"parking", func(r Restaurant) any { return r.Parking }},
+	{"wifi", func(r Restaurant) any { return r.WiFi }},
+	{"wheelchairAccessible", func(r Restaurant) any { return r.WheelchairAccessible }},
+}
+
+// ExportContentType returns the MIME type of an export format.
+func ExportContentType(format string) string {
+	switch format {
+	case ExportFormatCSV:
+		return "text/csv"
+	case ExportFormatXML:
+		return "application/xml"
+	default:
+		return "application/x-ndjson"
+	}
+}
+
+// selectExportFields returns the fields named in columns, or every field when columns is empty.
+func selectExportFields(columns []string) ([]exportField, error) {
+	if len(columns) == 0 {
+		return exportFields, nil
+	}
+	fields := make([]exportField, 0, len(columns))
+	for _, column := range columns {
+		column = strings.TrimSpace(column)
+		found := false
+		for _, f := range exportFields {
+			if f.name == column {
+				fields = append(fields, f)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown export column %q", column)
+		}
+	}
+	return fields, nil
+}
+
+// restaurantWriter writes restaurants in one export format.
+type restaurantWriter interface {
+	begin() error
+	write(r Restaurant) error
+	end() error
+}
+
+// newRestaurantWriter returns a writer for the format limited to the given fields.
+func newRestaurantWriter(w io.Writer, format string, fields []exportField) (restaurantWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return &csvRestaurantWriter{w: csv.NewWriter(w), fields: fields}, nil
+	case ExportFormatJSON:
+		return &jsonRestaurantWriter{w: w, fields: fields}, nil
+	case ExportFormatXML:
+		return &xmlRestaurantWriter{w: w, enc: xml.NewEncoder(w), fields: fields}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// ValidateExport reports whether an export with the given format and columns
+// can be produced, so callers can reject bad requests before streaming output.
+func ValidateExport(format string, columns []string) error {
+	fields, err := selectExportFields(columns)
+	if err != nil {
+		return err
+	}
+	_, err = newRestaurantWriter(io.Discard, format, fields)
+	return err
+}
+
+// ExportRestaurants streams every restaurant to w in the given format, limited
+// to the named columns (all columns when empty). Rows are written as they are
+// read from the database rather than being loaded into memory first.
+func ExportRestaurants(ctx context.Context, db *sql.DB, w io.Writer, format string, columns []string) error {
+	return exportRestaurants(ctx, db, w, format, columns, nil)
+}
+
+// exportRestaurants is ExportRestaurants, calling started, if not nil, once the
+// query has succeeded and before anything is written to w.
+func exportRestaurants(ctx context.Context, db *sql.DB, w io.Writer, format string, columns []string, started func()) error {
+	fields, err := selectExportFields(columns)
+	if err != nil {
+		return err
+	}
+	rw, err := newRestaurantWriter(w, format, fields)
+	if err != nil {
+		return err
+	}
+	opened := func() error {
+		if started != nil {
+			started()
+		}
+		return rw.begin()
+	}
+	if err := forEachRestaurant(ctx, db, opened, rw.write); err != nil {
+		return err
+	}
+	return rw.end()
+}
+
+// csvRestaurantWriter writes a header row followed by one row per restaurant.
+type csvRestaurantWriter struct {
+	w      *csv.Writer
+	fields []exportField
+}
+
+func (c *csvRestaurantWriter) begin() error {
+	header := make([]string, len(c.fields))
+	for i, f := range c.fields {
+		header[i] = f.name
+	}
+	return c.w.Write(header)
+}
+
+func (c *csvRestaurantWriter) write(r Restaurant) error {
+	record := make([]string, len(c.fields))
+	for i, f := range c.fields {
+		record[i] = fmt.Sprint(f.value(r))
+	}
+	if err := c.w.Write(record); err != nil {
+		return err
+	}
+	// Flush per row so output reaches the client as it is produced.
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvRestaurantWriter) end() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonRestaurantWriter writes one JSON object per line, keeping the field order.
+type jsonRestaurantWriter struct {
+	w      io.Writer
+	fields []exportField
+}
+
+func (j *jsonRestaurantWriter) begin() error { return nil }
+
+func (j *jsonRestaurantWriter) write(r Restaurant) error {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, f := range j.fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		value, err := json.Marshal(f.value(r))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "%q:%s", f.name, value)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(j.w, b.String())
+	return err
+}
+
+func (j *jsonRestaurantWriter) end() error { return nil }
+
+// xmlRestaurantWriter writes a <restaurants> document with one <restaurant> element per record.
+type xmlRestaurantWriter struct {
+	w      io.Writer
+	enc    *xml.Encoder
+	fields []exportField
+}
+
+func (x *xmlRestaurantWriter) begin() error {
+	if _, err := io.WriteString(x.w, xml.Header); err != nil {
+		return err
+	}
+	return x.enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: "restaurants"}})
+}
+
+func (x *xmlRestaurantWriter) write(r Restaurant) error {
+	start := xml.StartElement{Name: xml.Name{Local: "restaurant"}}
+	if err := x.enc.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, f := range x.fields {
+		if err := x.enc.EncodeElement(f.value(r), xml.StartElement{Name: xml.Name{Local: f.name}}); err != nil {
+			return err
+		}
+	}
+	if err := x.enc.EncodeToken(start.End()); err != nil {
+		return err
+	}
+	return x.enc.Flush()
+}
+
+func (x *xmlRestaurantWriter) end() error {
+	if err := x.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "restaurants"}}); err != nil {
+		return err
+	}
+	if err := x.enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(x.w, "\n")
+	return err
+}
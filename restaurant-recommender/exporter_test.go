@@ -0,0 +1,198 @@
+package restaurantrecommender
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// newExportDB returns a mock DB expecting a single full catalogue query with two restaurants.
+func newExportDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT " + selectRestaurantColumns + " FROM restaurants")).
+		WillReturnRows(sqlmock.NewRows(restaurantRowColumns).
+			AddRow("3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70", "Pizza Hut", "Italian", "Wherever Street 99, Somewhere", "09:00", "23:00", true, true,
+				"+1 555 0100", "https://www.pizzahut.com", "info@pizzahut.example", 80, true, true, true).
+			AddRow("8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d", "Taco Bell", "Mexican", "123 Burrito Blvd, Somecity", "10:00", "22:00", false, true,
+				"+1 555 0101", "https://www.tacobell.com", "info@tacobell.example", 40, true, false, true))
+	return db, mock
+}
+
+// TestExportRestaurants_CSV tests CSV output with column selection.
+func TestExportRestaurants_CSV(t *testing.T) {
+	db, mock := newExportDB(t)
+
+	var buf bytes.Buffer
+	if err := ExportRestaurants(context.Background(), db, &buf, ExportFormatCSV, []string{"name", "address", "vegetarian"}); err != nil {
+		t.Fatalf("ExportRestaurants returned error: %v", err)
+	}
+	want := `name,address,vegetarian
+Pizza Hut,"Wherever Street 99, Somewhere",true
+Taco Bell,"123 Burrito Blvd, Somecity",false
+`
+	if buf.String() != want {
+		t.Errorf("unexpected CSV output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// TestExportRestaurants_CSVRoundTrip tests that a full CSV export can be imported again.
+func TestExportRestaurants_CSVRoundTrip(t *testing.T) {
+	db, _ := newExportDB(t)
+
+	var buf bytes.Buffer
+	if err := ExportRestaurants(context.Background(), db, &buf, ExportFormatCSV, nil); err != nil {
+		t.Fatalf("ExportRestaurants returned error: %v", err)
+	}
+	rows, err := decodeCSVRows(&buf)
+	if err != nil {
+		t.Fatalf("decodeCSVRows returned error: %v", err)
+	}
+	if len(rows) != 2 || rows[1].err != nil {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+	if got := rows[1].restaurant; got.Name != "Taco Bell" || got.SeatingCapacity != 40 || !got.Parking || got.WiFi {
+		t.Errorf("unexpected round-tripped restaurant: %+v", got)
+	}
+}
+
+// TestExportRestaurants_JSON tests JSON lines output keeps the selected column order.
+func TestExportRestaurants_JSON(t *testing.T) {
+	db, _ := newExportDB(t)
+
+	var buf bytes.Buffer
+	if err := ExportRestaurants(context.Background(), db, &buf, ExportFormatJSON, []string{"style", "name", "seatingCapacity"}); err != nil {
+		t.Fatalf("ExportRestaurants returned error: %v", err)
+	}
+	want := `{"style":"Italian","name":"Pizza Hut","seatingCapacity":80}
+{"style":"Mexican","name":"Taco Bell","seatingCapacity":40}
+`
+	if buf.String() != want {
+		t.Errorf("unexpected JSON lines output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+// TestExportRestaurants_XML tests that XML output is a well-formed document.
+func TestExportRestaurants_XML(t *testing.T) {
+	db, _ := newExportDB(t)
+
+	var buf bytes.Buffer
+	if err := ExportRestaurants(context.Background(), db, &buf, ExportFormatXML, []string{"id", "name"}); err != nil {
+		t.Fatalf("ExportRestaurants returned error: %v", err)
+	}
+
+	var doc struct {
+		Restaurants []struct {
+			ID   string `xml:"id"`
+			Name string `xml:"name"`
+		} `xml:"restaurant"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("failed to parse XML output %q: %v", buf.String(), err)
+	}
+	if len(doc.Restaurants) != 2 || doc.Restaurants[0].Name != "Pizza Hut" || doc.Restaurants[1].ID != "8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d" {
+		t.Errorf("unexpected XML restaurants: %+v", doc.Restaurants)
+	}
+}
+
+// TestValidateExport tests rejection of unknown formats and columns.
+func TestValidateExport(t *testing.T) {
+	if err := ValidateExport(ExportFormatCSV, []string{"name", "stars"}); err == nil || !strings.Contains(err.Error(), "stars") {
+		t.Errorf("expected unknown column error, got %v", err)
+	}
+	if err := ValidateExport("geojson", nil); err == nil {
+		t.Error("expected unsupported format error")
+	}
+	if err := ValidateExport(ExportFormatXML, []string{"name"}); err != nil {
+		t.Errorf("expected valid export, got %v", err)
+	}
+}
+
+// TestExportRestaurantsHandler tests the export endpoint's headers and validation.
+func TestExportRestaurantsHandler(t *testing.T) {
+	db, mock := newExportDB(t)
+	handler := ExportRestaurantsHandler(db)
+
+	req := httptest.NewRequest(http.MethodGet, "/restaurants/export?format=json&columns=name", nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("expected Content-Type application/x-ndjson, got %q", ct)
+	}
+	if rec.Body.String() != "{\"name\":\"Pizza Hut\"}\n{\"name\":\"Taco Bell\"}\n" {
+		t.Errorf("unexpected body %q", rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/restaurants/export?columns=stars", nil)
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unknown column, got %d", rec.Code)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// TestExportRestaurantsHandler_StoreFailure tests that a catalogue query that
+// fails to open is reported as a problem in every format, with no export
+// headers or partial output.
+func TestExportRestaurantsHandler_StoreFailure(t *testing.T) {
+	tests := []struct {
+		format string
+		err    error
+		status int
+	}{
+		{ExportFormatCSV, errors.New("connection refused"), http.StatusServiceUnavailable},
+		{ExportFormatJSON, errors.New("connection refused"), http.StatusServiceUnavailable},
+		{ExportFormatXML, context.DeadlineExceeded, http.StatusGatewayTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to open sqlmock DB: %v", err)
+			}
+			defer db.Close()
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT " + selectRestaurantColumns + " FROM restaurants")).WillReturnError(tt.err)
+
+			req := httptest.NewRequest(http.MethodGet, "/restaurants/export?format="+tt.format, nil)
+			rec := httptest.NewRecorder()
+			checkContract(t, ExportRestaurantsHandler(db)).ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("expected a problem response, got Content-Type %q", ct)
+			}
+			if cd := rec.Header().Get("Content-Disposition"); cd != "" {
+				t.Errorf("expected no Content-Disposition, got %q", cd)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
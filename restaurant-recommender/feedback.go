@@ -0,0 +1,95 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxFeedbackComment bounds the length of a feedback comment, in characters.
+const maxFeedbackComment = 1000
+
+// Feedback is a diner's rating of a restaurant. RestaurantID is the public ID
+// of the restaurant, as returned in recommendations.
+type Feedback struct {
+	ID           int64     `json:"id"`
+	RestaurantID string    `json:"restaurantId"`
+	Rating       int       `json:"rating"`
+	Comment      string    `json:"comment"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// FeedbackInput is the body of a feedback request.
+type FeedbackInput struct {
+	Rating  int    `json:"rating"`
+	Comment string `json:"comment"`
+}
+
+// validateFeedback checks that feedback can be stored.
+func validateFeedback(f FeedbackInput) error {
+	var errs []error
+	if f.Rating < 1 || f.Rating > 5 {
+		errs = append(errs, fmt.Errorf("rating must be from 1 to 5, got %d", f.Rating))
+	}
+	if n := len([]rune(f.Comment)); n > maxFeedbackComment {
+		errs = append(errs, fmt.Errorf("comment must be at most %d characters, got %d", maxFeedbackComment, n))
+	}
+	return errors.Join(errs...)
+}
+
+// addFeedback stores feedback on the restaurant with f.RestaurantID, along with
+// the ID of the request that sent it, and returns it with its ID and time.
+func addFeedback(ctx context.Context, db *sql.DB, f Feedback) (_ Feedback, err error) {
+	requestID := RequestIDFromContext(ctx)
+	ctx, finish := withQueryTimeout(ctx, "addFeedback")
+	defer finish(&err)
+
+	err = db.QueryRowContext(ctx,
+		`INSERT INTO restaurant_feedback (restaurant_id, rating, comment, request_id)
+        OUTPUT INSERTED.id, INSERTED.created_at
+        SELECT id, @p2, @p3, @p4 FROM restaurants WHERE publicId = @p1`,
+		sql.Named("p1", f.RestaurantID),
+		sql.Named("p2", f.Rating),
+		sql.Named("p3", f.Comment),
+		sql.Named("p4", sql.NullString{String: requestID, Valid: requestID != ""}),
+	).Scan(&f.ID, &f.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Feedback{}, errRestaurantNotFound
+	}
+	return f, err
+}
+
+// FeedbackHandler returns a handler that records feedback on the restaurant
+// named by the {id} path value, the public ID included in recommendations.
+func FeedbackHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := restaurantID(r)
+		if err != nil {
+			writeError(w, r, CodeInvalidRequest, err.Error())
+			return
+		}
+		var input FeedbackInput
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&input); err != nil {
+			writeError(w, r, CodeInvalidRequest, fmt.Sprintf("invalid request body: %v", err))
+			return
+		}
+		input.Comment = strings.TrimSpace(input.Comment)
+		if err := validateFeedback(input); err != nil {
+			writeError(w, r, CodeValidationFailed, err.Error())
+			return
+		}
+		feedback, err := addFeedback(r.Context(), db, Feedback{RestaurantID: id, Rating: input.Rating, Comment: input.Comment})
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+		writeJSON(w, r, http.StatusCreated, feedback)
+	}
+}
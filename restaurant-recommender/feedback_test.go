@@ -0,0 +1,104 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// postFeedback sends a feedback body for the restaurant with the given ID to handler.
+func postFeedback(handler http.Handler, id, body string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.Handle("POST /restaurants/{id}/feedback", handler)
+	req := httptest.NewRequest(http.MethodPost, "/restaurants/"+id+"/feedback", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(WithRequestID(req.Context(), "req-1"))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestFeedbackHandler tests that feedback is stored against the restaurant
+// with the public ID in the path.
+func TestFeedbackHandler(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	const id = "3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70"
+	created := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, @p2, @p3, @p4 FROM restaurants WHERE publicId = @p1")).
+		WithArgs(id, 5, "Lovely pizza", "req-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, created))
+
+	rec := postFeedback(checkContract(t, FeedbackHandler(db)), strings.ToUpper(id), `{"rating": 5, "comment": "  Lovely pizza "}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var feedback Feedback
+	if err := json.Unmarshal(rec.Body.Bytes(), &feedback); err != nil {
+		t.Fatalf("Error unmarshalling response JSON: %v", err)
+	}
+	want := Feedback{ID: 7, RestaurantID: id, Rating: 5, Comment: "Lovely pizza", CreatedAt: created}
+	if feedback != want {
+		t.Errorf("Expected %+v, got %+v", want, feedback)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
+
+// TestFeedbackHandler_Errors tests the responses to unusable feedback.
+func TestFeedbackHandler_Errors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	const id = "3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70"
+	mock.ExpectQuery("INSERT INTO restaurant_feedback").WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+	mock.ExpectQuery("INSERT INTO restaurant_feedback").WillReturnError(context.DeadlineExceeded)
+	handler := checkContract(t, FeedbackHandler(db))
+
+	tests := []struct {
+		name   string
+		id     string
+		body   string
+		status int
+		code   ErrorCode
+	}{
+		{"unknown restaurant", id, `{"rating": 3}`, http.StatusNotFound, CodeRestaurantNotFound},
+		{"store timeout", id, `{"rating": 3}`, http.StatusGatewayTimeout, CodeStoreTimeout},
+		{"internal id", "7", `{"rating": 3}`, http.StatusBadRequest, CodeInvalidRequest},
+		{"unknown field", id, `{"rating": 3, "stars": 3}`, http.StatusBadRequest, CodeInvalidRequest},
+		{"rating out of range", id, `{"rating": 0}`, http.StatusUnprocessableEntity, CodeValidationFailed},
+		{"comment too long", id, `{"rating": 2, "comment": "` + strings.Repeat("a", maxFeedbackComment+1) + `"}`,
+			http.StatusUnprocessableEntity, CodeValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := postFeedback(handler, tt.id, tt.body)
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+			if problem := decodeError(t, rec); problem.Code != tt.code {
+				t.Errorf("Expected code %s, got %s", tt.code, problem.Code)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
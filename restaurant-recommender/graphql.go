@@ -0,0 +1,568 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Limits on a GraphQL document, checked before it is executed. A document may
+// select recommend under several aliases; each is answered like a query in a
+// batch, so they are capped like one.
+const (
+	maxGraphQLDepth      = 15
+	maxGraphQLComplexity = 500
+	maxGraphQLRecommends = maxBatchQueries
+)
+
+// graphQLRequest is a GraphQL request, sent as a JSON body or as GET parameters.
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables"`
+	OperationName string         `json:"operationName"`
+}
+
+// graphQLError is a resolver error reported with one of the API's error codes
+// in its "extensions", so GraphQL clients can handle errors like REST clients.
+type graphQLError struct {
+	code    ErrorCode
+	message string
+}
+
+// Error returns the error message.
+func (e *graphQLError) Error() string { return e.message }
+
+// Extensions returns the error code for the GraphQL error's "extensions".
+func (e *graphQLError) Extensions() map[string]any {
+	return map[string]any{"code": string(e.code)}
+}
+
+// newGraphQLError returns a resolver error with the given code, using the
+// code's title when message is empty.
+func newGraphQLError(code ErrorCode, message string) error {
+	if message == "" {
+		message = errorKinds[code].title
+	}
+	return &graphQLError{code: code, message: message}
+}
+
+// graphQLStoreError logs a data-layer error and returns it as a resolver error.
+func graphQLStoreError(ctx context.Context, msg string, err error) error {
+	logStoreError(ctx, msg, err)
+	return newGraphQLError(storeErrorCode(err), msg)
+}
+
+// restaurantType is the GraphQL Restaurant; fields resolve through the JSON tags.
+var restaurantType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Restaurant",
+	Fields: graphql.Fields{
+		"id":                   {Type: graphql.NewNonNull(graphql.ID)},
+		"name":                 {Type: graphql.NewNonNull(graphql.String)},
+		"style":                {Type: graphql.NewNonNull(graphql.String)},
+		"address":              {Type: graphql.NewNonNull(graphql.String)},
+		"openHour":             {Type: graphql.NewNonNull(graphql.String)},
+		"closeHour":            {Type: graphql.NewNonNull(graphql.String)},
+		"vegetarian":           {Type: graphql.NewNonNull(graphql.Boolean)},
+		"deliveries":           {Type: graphql.NewNonNull(graphql.Boolean)},
+		"phone":                {Type: graphql.NewNonNull(graphql.String)},
+		"website":              {Type: graphql.NewNonNull(graphql.String)},
+		"email":                {Type: graphql.NewNonNull(graphql.String)},
+		"seatingCapacity":      {Type: graphql.NewNonNull(graphql.Int)},
+		"parking":              {Type: graphql.NewNonNull(graphql.Boolean)},
+		"wifi":                 {Type: graphql.NewNonNull(graphql.Boolean)},
+		"wheelchairAccessible": {Type: graphql.NewNonNull(graphql.Boolean)},
+	},
+})
+
+// relaxationType is the GraphQL enum of Relaxation values.
+var relaxationType = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "Relaxation",
+	Description: "A constraint loosened to suggest an alternative.",
+	Values: graphql.EnumValueConfigMap{
+		"OPEN_TIME": {Value: RelaxOpenTime},
+		"DELIVERS":  {Value: RelaxDelivery},
+		"WIFI":      {Value: RelaxWiFi},
+		"PARKING":   {Value: RelaxParking},
+		"STYLE":     {Value: RelaxStyle},
+	},
+})
+
+// restaurantPageType is the GraphQL RestaurantPage, resolved from a RestaurantPage.
+var restaurantPageType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "RestaurantPage",
+	Description: "A page of the catalogue.",
+	Fields: graphql.Fields{
+		"results": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(restaurantType)))},
+		"nextCursor": {
+			Type:        graphql.String,
+			Description: "Passed back as cursor, with the same sort, for the next page; null on the last page.",
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				if next := p.Source.(RestaurantPage).NextCursor; next != "" {
+					return next, nil
+				}
+				return nil, nil
+			},
+		},
+		"limit": {Type: graphql.NewNonNull(graphql.Int)},
+	},
+})
+
+// alternativeType is the GraphQL Alternative; "restaurant" resolves to the
+// embedded Restaurant.
+var alternativeType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Alternative",
+	Fields: graphql.Fields{
+		"restaurant": {Type: graphql.NewNonNull(restaurantType)},
+		"relaxed":    {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(relaxationType)))},
+	},
+})
+
+// criteriaFields are the fields shared by the Criteria output type and the
+// RestaurantFilter input type, named like QueryCriteria's JSON.
+var criteriaFields = []struct {
+	name, description string
+	typ               graphql.Output
+}{
+	{"style", "Cuisine, e.g. Italian.", graphql.String},
+	{"vegetarian", "Serves vegetarian food.", graphql.Boolean},
+	{"delivers", "Delivers.", graphql.Boolean},
+	{"parking", "Has parking.", graphql.Boolean},
+	{"wifi", "Has wifi.", graphql.Boolean},
+	{"accessible", "Is wheelchair accessible.", graphql.Boolean},
+	{"openNow", "Is open now.", graphql.Boolean},
+	{"openAt", "Is open at this time of day, in HH:MM format.", graphql.String},
+}
+
+// criteriaType is the GraphQL Criteria, the filters a query was answered with.
+var criteriaType = func() *graphql.Object {
+	fields := graphql.Fields{}
+	for _, f := range criteriaFields {
+		fields[f.name] = &graphql.Field{Type: f.typ, Description: f.description}
+	}
+	fields["openNow"].Type = graphql.NewNonNull(graphql.Boolean)
+	fields["openAt"].Resolve = func(p graphql.ResolveParams) (any, error) {
+		if c := p.Source.(QueryCriteria); c.OpenAt != nil {
+			return c.OpenAt.Format("15:04"), nil
+		}
+		return nil, nil
+	}
+	return graphql.NewObject(graphql.ObjectConfig{Name: "Criteria", Fields: fields})
+}()
+
+// filterType is the GraphQL RestaurantFilter input. Fields left out do not filter.
+var filterType = func() *graphql.InputObject {
+	fields := graphql.InputObjectConfigFieldMap{}
+	for _, f := range criteriaFields {
+		fields[f.name] = &graphql.InputObjectFieldConfig{Type: f.typ, Description: f.description}
+	}
+	return graphql.NewInputObject(graphql.InputObjectConfig{Name: "RestaurantFilter", Fields: fields})
+}()
+
+// recommendationType is the GraphQL Recommendation, resolved from a recommendation.
+var recommendationType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Recommendation",
+	Fields: graphql.Fields{
+		"query": {
+			Type: graphql.String,
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				if q := p.Source.(recommendation).query; q != "" {
+					return q, nil
+				}
+				return nil, nil
+			},
+		},
+		"criteria": {
+			Type: graphql.NewNonNull(criteriaType),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return p.Source.(recommendation).criteria, nil
+			},
+		},
+		"total": {
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of restaurants matching, before the limit.",
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return len(p.Source.(recommendation).matches), nil
+			},
+		},
+		"results": {
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(restaurantType))),
+			Args: graphql.FieldConfigArgument{
+				"limit": {Type: graphql.Int, DefaultValue: defaultRecommendLimit},
+			},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				limit := p.Args["limit"].(int)
+				if limit < 1 || limit > maxRecommendLimit {
+					return nil, newGraphQLError(CodeInvalidRequest, fmt.Sprintf("limit must be from 1 to %d", maxRecommendLimit))
+				}
+				matches := p.Source.(recommendation).matches
+				if matches == nil {
+					return []Restaurant{}, nil
+				}
+				return matches[:min(limit, len(matches))], nil
+			},
+		},
+		"alternatives": {
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(alternativeType))),
+			Description: "Suggestions relaxing some criteria, when nothing matches exactly.",
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				if alternatives := p.Source.(recommendation).alternatives; alternatives != nil {
+					return alternatives, nil
+				}
+				return []Alternative{}, nil
+			},
+		},
+		"relaxed": {
+			Type: graphql.NewNonNull(graphql.Boolean),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return len(p.Source.(recommendation).alternatives) > 0, nil
+			},
+		},
+	},
+})
+
+// queryLogStatsType is the GraphQL QueryLogStats, resolved through the JSON tags.
+var queryLogStatsType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "QueryLogStats",
+	Description: "Counters of the query-log writer since the service started.",
+	Fields: graphql.Fields{
+		"enqueued": {Type: graphql.NewNonNull(graphql.Int)},
+		"written":  {Type: graphql.NewNonNull(graphql.Int)},
+		"dropped":  {Type: graphql.NewNonNull(graphql.Int)},
+		"failed":   {Type: graphql.NewNonNull(graphql.Int)},
+		"pending":  {Type: graphql.NewNonNull(graphql.Int)},
+		"inFlight": {Type: graphql.NewNonNull(graphql.Int)},
+	},
+})
+
+// filterCriteria overrides criteria with the fields set in a RestaurantFilter.
+func filterCriteria(criteria *QueryCriteria, filter map[string]any) error {
+	if style, ok := filter["style"].(string); ok {
+		criteria.Style = style
+	}
+	flags := map[string]**bool{
+		"vegetarian": &criteria.Vegetarian,
+		"delivers":   &criteria.Delivers,
+		"parking":    &criteria.Parking,
+		"wifi":       &criteria.WiFi,
+		"accessible": &criteria.Accessible,
+	}
+	for name, dst := range flags {
+		if v, ok := filter[name].(bool); ok {
+			*dst = &v
+		}
+	}
+	if openNow, ok := filter["openNow"].(bool); ok {
+		criteria.OpenNow = openNow
+	}
+	if openAt, ok := filter["openAt"].(string); ok {
+		if err := validateHour("openAt", openAt); err != nil {
+			return err
+		}
+		t, _ := parseTime(openAt)
+		criteria.OpenAt = &t
+	}
+	return nil
+}
+
+// newGraphQLSchema builds the GraphQL schema, resolving from the catalogue and
+// query logs in the same way as the REST endpoints.
+func newGraphQLSchema(db *sql.DB, cache *CatalogueCache, logs *QueryLogWriter) (graphql.Schema, error) {
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"restaurants": {
+				Type:        graphql.NewNonNull(restaurantPageType),
+				Description: "Browse the catalogue a page at a time, sorted and filtered in the database like GET /restaurants.",
+				Args: graphql.FieldConfigArgument{
+					"filter": {Type: filterType},
+					"limit":  {Type: graphql.Int, DefaultValue: defaultRestaurantsLimit},
+					"sort": {
+						Type:         graphql.String,
+						DefaultValue: "name",
+						Description:  "name, style or closeHour, prefixed with - for descending.",
+					},
+					"cursor": {Type: graphql.String, Description: "The nextCursor of the previous page."},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					opts := listOptions{limit: p.Args["limit"].(int)}
+					if opts.limit < 1 || opts.limit > maxRestaurantsLimit {
+						return nil, newGraphQLError(CodeInvalidRequest, fmt.Sprintf("limit must be from 1 to %d", maxRestaurantsLimit))
+					}
+					var err error
+					if opts.sort, opts.desc, err = parseListSort(p.Args["sort"].(string)); err != nil {
+						return nil, newGraphQLError(CodeInvalidRequest, err.Error())
+					}
+					if cursor, ok := p.Args["cursor"].(string); ok {
+						if opts.after, err = parseListCursor(cursor, opts.sort, opts.desc); err != nil {
+							return nil, newGraphQLError(CodeInvalidRequest, err.Error())
+						}
+					}
+					if filter, ok := p.Args["filter"].(map[string]any); ok {
+						if err := filterCriteria(&opts.criteria, filter); err != nil {
+							return nil, newGraphQLError(CodeInvalidRequest, err.Error())
+						}
+					}
+
+					restaurants, next, err := listRestaurants(p.Context, db, opts, time.Now())
+					if err != nil {
+						return nil, graphQLStoreError(p.Context, "Error retrieving restaurants", err)
+					}
+					page := RestaurantPage{Results: restaurants, Limit: opts.limit}
+					if next != nil {
+						page.NextCursor = next.String()
+					}
+					return page, nil
+				},
+			},
+			"restaurant": {
+				Type:        restaurantType,
+				Description: "Look up a restaurant by ID; null if there is none.",
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, err := uuid.Parse(p.Args["id"].(string))
+					if err != nil {
+						return nil, newGraphQLError(CodeInvalidRequest, fmt.Sprintf("invalid restaurant id %q", p.Args["id"]))
+					}
+					restaurant, err := getRestaurant(p.Context, db, id.String())
+					if errors.Is(err, errRestaurantNotFound) {
+						return nil, nil
+					}
+					if err != nil {
+						return nil, graphQLStoreError(p.Context, "Error accessing restaurants", err)
+					}
+					return restaurant, nil
+				},
+			},
+			"styles": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				Description: "The cuisines in the catalogue.",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					styles, err := catalogueStyles(p.Context, db, cache)
+					if err != nil {
+						return nil, graphQLStoreError(p.Context, "Error retrieving restaurant styles", err)
+					}
+					return styles, nil
+				},
+			},
+			"recommend": {
+				Type:        graphql.NewNonNull(recommendationType),
+				Description: "Recommend restaurants for a free-text query, structured filters or both; filters override the query.",
+				Args: graphql.FieldConfigArgument{
+					"query":   {Type: graphql.String},
+					"filters": {Type: filterType},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					ctx, span := tracer.Start(p.Context, "Recommend",
+						trace.WithAttributes(attribute.String("api.version", "graphql")))
+					defer span.End()
+
+					text, _ := p.Args["query"].(string)
+					filter, hasFilter := p.Args["filters"].(map[string]any)
+					if text == "" && !hasFilter {
+						return nil, newGraphQLError(CodeQueryRequired, "query or filters is required")
+					}
+					var criteria QueryCriteria
+					if text != "" {
+						styles, err := catalogueStyles(ctx, db, cache)
+						if err != nil {
+							span.SetStatus(otelcodes.Error, err.Error())
+							return nil, graphQLStoreError(ctx, "Error retrieving restaurant styles", err)
+						}
+						criteria = parseCriteria(ctx, text, styles)
+					}
+					if err := filterCriteria(&criteria, filter); err != nil {
+						return nil, newGraphQLError(CodeInvalidRequest, err.Error())
+					}
+
+					// Requests with only filters are logged as those criteria.
+					logged := text
+					if logged == "" {
+						b, _ := json.Marshal(criteria)
+						logged = string(b)
+					}
+					rec, err := answerRecommendation(ctx, db, cache, logs, logged, criteria)
+					if err != nil {
+						span.SetStatus(otelcodes.Error, err.Error())
+						return nil, graphQLStoreError(ctx, "Error retrieving restaurants", err)
+					}
+					rec.query = text
+					return rec, nil
+				},
+			},
+			"queryLogStats": {
+				Type:        queryLogStatsType,
+				Description: "Query-log writer counters; null when query logging is disabled.",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					if logs == nil {
+						return nil, nil
+					}
+					return logs.Stats(), nil
+				},
+			},
+		},
+	})
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+// parseGraphQLRequest reads a GraphQL request from a POST JSON body or from
+// the "query", "variables" and "operationName" parameters of a GET.
+func parseGraphQLRequest(w http.ResponseWriter, r *http.Request) (graphQLRequest, error) {
+	var req graphQLRequest
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		req.Query, req.OperationName = q.Get("query"), q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				return req, fmt.Errorf("invalid variables: %w", err)
+			}
+		}
+	} else if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+		return req, fmt.Errorf("invalid request body: %w", err)
+	}
+	if req.Query == "" {
+		return req, errors.New("query is required")
+	}
+	return req, nil
+}
+
+// graphQLCost is the shape of a GraphQL document: how deeply its fields nest,
+// how many fields it selects with fragments expanded, and how many times it
+// selects recommend.
+type graphQLCost struct {
+	depth, fields, recommends int
+}
+
+// measureGraphQL measures every operation in doc. It stops once the document
+// is over the depth or complexity limit, so that measuring fragments spread
+// many times is not itself expensive.
+func measureGraphQL(doc *ast.Document) graphQLCost {
+	fragments := map[string]*ast.FragmentDefinition{}
+	var operations []*ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.FragmentDefinition:
+			fragments[def.Name.Value] = def
+		case *ast.OperationDefinition:
+			operations = append(operations, def)
+		}
+	}
+
+	var cost graphQLCost
+	// Fragments being expanded are tracked, since cycles are only rejected
+	// later, by validation.
+	spreading := map[string]bool{}
+	var walk func(set *ast.SelectionSet, depth int)
+	walk = func(set *ast.SelectionSet, depth int) {
+		if set == nil || cost.depth > maxGraphQLDepth || cost.fields > maxGraphQLComplexity {
+			return
+		}
+		for _, selection := range set.Selections {
+			switch s := selection.(type) {
+			case *ast.Field:
+				cost.fields++
+				cost.depth = max(cost.depth, depth+1)
+				if depth == 0 && s.Name.Value == "recommend" {
+					cost.recommends++
+				}
+				walk(s.SelectionSet, depth+1)
+			case *ast.InlineFragment:
+				walk(s.SelectionSet, depth)
+			case *ast.FragmentSpread:
+				name := s.Name.Value
+				if fragment, ok := fragments[name]; ok && !spreading[name] {
+					spreading[name] = true
+					walk(fragment.SelectionSet, depth)
+					delete(spreading, name)
+				}
+			}
+		}
+	}
+	for _, operation := range operations {
+		walk(operation.SelectionSet, 0)
+	}
+	return cost
+}
+
+// checkGraphQLLimits returns an error if a GraphQL document nests too deeply,
+// selects too many fields or selects recommend too many times. Documents that
+// do not parse are left for execution to report.
+func checkGraphQLLimits(query string) *graphQLError {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return nil
+	}
+	cost := measureGraphQL(doc)
+	var message string
+	switch {
+	case cost.depth > maxGraphQLDepth:
+		message = fmt.Sprintf("query is nested more than %d levels deep", maxGraphQLDepth)
+	case cost.fields > maxGraphQLComplexity:
+		message = fmt.Sprintf("query selects more than %d fields", maxGraphQLComplexity)
+	case cost.recommends > maxGraphQLRecommends:
+		message = fmt.Sprintf("query selects recommend %d times; at most %d are allowed", cost.recommends, maxGraphQLRecommends)
+	default:
+		return nil
+	}
+	return &graphQLError{code: CodeInvalidRequest, message: message}
+}
+
+// GraphQLHandler returns a handler serving GraphQL queries over the catalogue,
+// recommendations and query-log stats. Requests that are not GraphQL at all
+// get a problem response; GraphQL errors are reported in the result's "errors".
+func GraphQLHandler(db *sql.DB, cache *CatalogueCache, logs *QueryLogWriter) http.HandlerFunc {
+	schema, schemaErr := newGraphQLSchema(db, cache, logs)
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "GraphQLHandler")
+		defer span.End()
+
+		if schemaErr != nil {
+			slog.ErrorContext(ctx, "Error building the GraphQL schema", "err", schemaErr)
+			writeError(w, r, CodeInternal, "GraphQL schema unavailable")
+			return
+		}
+		req, err := parseGraphQLRequest(w, r)
+		if err != nil {
+			writeError(w, r, CodeInvalidRequest, err.Error())
+			return
+		}
+		span.SetAttributes(attribute.String("graphql.operation.name", req.OperationName))
+		if limitErr := checkGraphQLLimits(req.Query); limitErr != nil {
+			span.SetStatus(otelcodes.Error, limitErr.message)
+			writeJSON(w, r, http.StatusOK, &graphql.Result{Errors: []gqlerrors.FormattedError{{
+				Message:    limitErr.message,
+				Locations:  []location.SourceLocation{},
+				Extensions: limitErr.Extensions(),
+			}}})
+			return
+		}
+
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  req.Query,
+			VariableValues: req.Variables,
+			OperationName:  req.OperationName,
+			Context:        ctx,
+		})
+		if result.HasErrors() {
+			span.SetStatus(otelcodes.Error, result.Errors[0].Message)
+		}
+		writeJSON(w, r, http.StatusOK, result)
+	}
+}
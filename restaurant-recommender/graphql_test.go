@@ -0,0 +1,283 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+// graphQLResponse is a decoded GraphQL result.
+type graphQLResponse struct {
+	Data   map[string]any `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+// postGraphQL sends a GraphQL query to handler and decodes the result.
+func postGraphQL(t *testing.T, handler http.Handler, query string, variables map[string]any) graphQLResponse {
+	t.Helper()
+	body, _ := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp graphQLResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Error unmarshalling response JSON: %v", err)
+	}
+	return resp
+}
+
+// TestGraphQLHandler_Catalogue tests browsing the catalogue a page at a time
+// with the fields the client selects.
+func TestGraphQLHandler_Catalogue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	expectCatalogueQuery(mock)
+	// Pages are read from the database, one row more than the limit.
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT TOP (@p2)")).
+		WithArgs(false, 2).
+		WillReturnRows(sqlmock.NewRows(restaurantRowColumns).
+			AddRow("5d6e7f80-9a0b-4c1d-8e2f-3a4b5c6d7e8f", "Luigi's", "italian", "1 Pasta Lane", "12:00", "22:00", false, false,
+				"", "", "", 20, false, false, false).
+			AddRow("8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d", "Trattoria", "Italian", "2 Pasta Lane", "12:00", "22:00", false, false,
+				"", "", "", 30, false, false, false))
+	mock.ExpectQuery(regexp.QuoteMeta("(name > @p2 OR (name = @p2 AND publicId > @p3))")).
+		WithArgs(false, "Luigi's", "5d6e7f80-9a0b-4c1d-8e2f-3a4b5c6d7e8f", 2).
+		WillReturnRows(sqlmock.NewRows(restaurantRowColumns).
+			AddRow("8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d", "Trattoria", "Italian", "2 Pasta Lane", "12:00", "22:00", false, false,
+				"", "", "", 30, false, false, false))
+	handler := checkContract(t, GraphQLHandler(db, NewCatalogueCache(db, time.Minute), nil))
+
+	browse := `query Browse($filter: RestaurantFilter, $cursor: String) {
+		styles
+		restaurants(filter: $filter, limit: 1, cursor: $cursor) { results { name seatingCapacity } nextCursor limit }
+		queryLogStats { written }
+	}`
+	resp := postGraphQL(t, handler, browse, map[string]any{"filter": map[string]any{"vegetarian": false}})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Unexpected errors %+v", resp.Errors)
+	}
+	page, _ := resp.Data["restaurants"].(map[string]any)
+	cursor, _ := page["nextCursor"].(string)
+	if cursor == "" {
+		t.Fatalf("Expected a next cursor, got %v", resp.Data)
+	}
+	want := map[string]any{
+		"styles": []any{"Italian"},
+		"restaurants": map[string]any{
+			"results":    []any{map[string]any{"name": "Luigi's", "seatingCapacity": float64(20)}},
+			"nextCursor": cursor,
+			"limit":      float64(1),
+		},
+		"queryLogStats": nil,
+	}
+	if !reflect.DeepEqual(resp.Data, want) {
+		t.Errorf("Expected %v, got %v", want, resp.Data)
+	}
+
+	resp = postGraphQL(t, handler, browse, map[string]any{"filter": map[string]any{"vegetarian": false}, "cursor": cursor})
+	want["restaurants"] = map[string]any{
+		"results":    []any{map[string]any{"name": "Trattoria", "seatingCapacity": float64(30)}},
+		"nextCursor": nil,
+		"limit":      float64(1),
+	}
+	if !reflect.DeepEqual(resp.Data, want) {
+		t.Errorf("Expected %v, got %v", want, resp.Data)
+	}
+
+	for _, query := range []string{
+		`{ restaurants(limit: 500) { limit } }`,
+		`{ restaurants(sort: "address") { limit } }`,
+		`{ restaurants(sort: "-name", cursor: "` + cursor + `") { limit } }`,
+	} {
+		resp = postGraphQL(t, handler, query, nil)
+		if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != string(CodeInvalidRequest) {
+			t.Errorf("%s: expected an %s error, got %+v", query, CodeInvalidRequest, resp.Errors)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
+
+// TestGraphQLHandler_Recommend tests recommendations from a query, filters
+// overriding it, and alternatives.
+func TestGraphQLHandler_Recommend(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	expectCatalogueQuery(mock)
+	logs := NewQueryLogWriter(db, QueryLogConfig{QueueSize: 10, BatchSize: 10, FlushInterval: time.Hour})
+	handler := checkContract(t, GraphQLHandler(db, NewCatalogueCache(db, time.Minute), logs))
+
+	resp := postGraphQL(t, handler, `{
+		recommend(query: "italian", filters: {delivers: false}) {
+			query criteria { style delivers openAt } total results { name } relaxed
+		}
+	}`, nil)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Unexpected errors %+v", resp.Errors)
+	}
+	want := map[string]any{
+		"recommend": map[string]any{
+			"query":    "italian",
+			"criteria": map[string]any{"style": "Italian", "delivers": false, "openAt": nil},
+			"total":    float64(1),
+			"results":  []any{map[string]any{"name": "Luigi's"}},
+			"relaxed":  false,
+		},
+	}
+	if !reflect.DeepEqual(resp.Data, want) {
+		t.Errorf("Expected %v, got %v", want, resp.Data)
+	}
+
+	resp = postGraphQL(t, handler, `{
+		recommend(filters: {style: "Italian", openAt: "08:45"}) {
+			query results { name } alternatives { restaurant { name } relaxed }
+		}
+	}`, nil)
+	want = map[string]any{
+		"recommend": map[string]any{
+			"query":        nil,
+			"results":      []any{},
+			"alternatives": []any{map[string]any{"restaurant": map[string]any{"name": "Pizza Hut"}, "relaxed": []any{"OPEN_TIME"}}},
+		},
+	}
+	if !reflect.DeepEqual(resp.Data, want) {
+		t.Errorf("Expected %v, got %v", want, resp.Data)
+	}
+
+	resp = postGraphQL(t, handler, `{ recommend { total } }`, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != string(CodeQueryRequired) {
+		t.Errorf("Expected a %s error, got %+v", CodeQueryRequired, resp.Errors)
+	}
+
+	// Both answered queries were logged, the filters-only one as its criteria.
+	resp = postGraphQL(t, handler, `{ queryLogStats { enqueued } }`, nil)
+	want = map[string]any{"queryLogStats": map[string]any{"enqueued": float64(2)}}
+	if !reflect.DeepEqual(resp.Data, want) {
+		t.Errorf("Expected %v, got %v", want, resp.Data)
+	}
+	anyArg := sqlmock.AnyArg()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO query_logs")).
+		WithArgs("italian", anyArg, anyArg, anyArg, `{"style":"Italian","openAt":"08:45"}`, anyArg, anyArg, anyArg).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	if err := logs.Close(context.Background()); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
+
+// TestGraphQLHandler_Limits tests that documents nesting too deeply, selecting
+// too many fields or recommending too many times are rejected unexecuted,
+// while the standard introspection query is allowed.
+func TestGraphQLHandler_Limits(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	handler := checkContract(t, GraphQLHandler(db, nil, nil))
+
+	resp := postGraphQL(t, handler, testutil.IntrospectionQuery, nil)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Unexpected errors for the introspection query %+v", resp.Errors)
+	}
+
+	var recommends, fields strings.Builder
+	for i := range maxGraphQLRecommends + 1 {
+		fmt.Fprintf(&recommends, "r%d: recommend(query: \"pizza\") { total } ", i)
+	}
+	for i := range maxGraphQLComplexity + 1 {
+		fmt.Fprintf(&fields, "s%d: styles ", i)
+	}
+	tests := []struct {
+		name, query string
+	}{
+		{"depth", "{ __schema { types { " + strings.Repeat("ofType { ", maxGraphQLDepth) + "name" + strings.Repeat(" }", maxGraphQLDepth+2) + " }"},
+		{"fields", "{ " + fields.String() + "}"},
+		{"recommend aliases", "{ " + recommends.String() + "}"},
+		{"recommend through a fragment", "query { ...R } fragment R on Query { " + recommends.String() + "}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := postGraphQL(t, handler, tt.query, nil)
+			if resp.Data != nil || len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != string(CodeInvalidRequest) {
+				t.Errorf("Expected only an %s error, got %+v", CodeInvalidRequest, resp)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
+
+// TestGraphQLHandler_Errors tests requests that are not GraphQL and store
+// failures reported in the GraphQL errors.
+func TestGraphQLHandler_Errors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT DISTINCT style FROM restaurants").WillReturnError(context.DeadlineExceeded)
+	handler := checkContract(t, GraphQLHandler(db, nil, nil))
+
+	// Queries can also be sent as GET parameters.
+	req := httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape("{ styles }"), nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	var resp graphQLResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Error unmarshalling response JSON: %v", err)
+	}
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != string(CodeStoreTimeout) {
+		t.Errorf("Expected a %s error, got %+v", CodeStoreTimeout, resp.Errors)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader("{"))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d", rec.Code)
+	}
+	if problem := decodeError(t, rec); problem.Code != CodeInvalidRequest {
+		t.Errorf("Expected code %s, got %s", CodeInvalidRequest, problem.Code)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
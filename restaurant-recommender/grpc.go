@@ -0,0 +1,268 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	recommenderv1 "github.com/kuhlman-labs/restaurant-recommender/proto/recommender/v1"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain qualifies the error codes attached to gRPC errors.
+const errorDomain = "restaurant-recommender"
+
+// grpcCodes maps the API's error codes to gRPC status codes.
+var grpcCodes = map[ErrorCode]codes.Code{
+	CodeQueryRequired:      codes.InvalidArgument,
+	CodeNoMatch:            codes.NotFound,
+	CodeStoreUnavailable:   codes.Unavailable,
+	CodeStoreTimeout:       codes.DeadlineExceeded,
+	CodeRestaurantNotFound: codes.NotFound,
+	CodeInvalidRequest:     codes.InvalidArgument,
+	CodeValidationFailed:   codes.InvalidArgument,
+	CodeUnauthorized:       codes.Unauthenticated,
+	CodeInternal:           codes.Internal,
+	CodeClientClosed:       codes.Canceled,
+}
+
+// grpcError returns a gRPC status error for an error code, carrying the code
+// as an ErrorInfo reason so clients see the same codes as HTTP clients.
+func grpcError(code ErrorCode, detail string) error {
+	message := detail
+	if message == "" {
+		message = errorKinds[code].title
+	}
+	st, err := status.New(grpcCodes[code], message).WithDetails(&errdetails.ErrorInfo{
+		Reason: string(code),
+		Domain: errorDomain,
+	})
+	if err != nil {
+		return status.Error(grpcCodes[code], message)
+	}
+	return st.Err()
+}
+
+// relaxationProtos maps relaxations to their protobuf enum values.
+var relaxationProtos = map[Relaxation]recommenderv1.Relaxation{
+	RelaxOpenTime: recommenderv1.Relaxation_RELAXATION_OPEN_TIME,
+	RelaxDelivery: recommenderv1.Relaxation_RELAXATION_DELIVERS,
+	RelaxWiFi:     recommenderv1.Relaxation_RELAXATION_WIFI,
+	RelaxParking:  recommenderv1.Relaxation_RELAXATION_PARKING,
+	RelaxStyle:    recommenderv1.Relaxation_RELAXATION_STYLE,
+}
+
+// GRPCServer serves the RecommenderService from the same catalogue, parser
+// and matcher as the HTTP handlers.
+type GRPCServer struct {
+	recommenderv1.UnimplementedRecommenderServiceServer
+
+	db    *sql.DB
+	cache *CatalogueCache
+	logs  *QueryLogWriter
+}
+
+// NewGRPCServer returns the gRPC service. cache and logs may be nil, as for
+// RecommendHandler.
+func NewGRPCServer(db *sql.DB, cache *CatalogueCache, logs *QueryLogWriter) *GRPCServer {
+	return &GRPCServer{db: db, cache: cache, logs: logs}
+}
+
+// Recommend returns the restaurants matching the free-text query and the
+// structured criteria, which override what was parsed from the query.
+func (s *GRPCServer) Recommend(ctx context.Context, req *recommenderv1.RecommendRequest) (*recommenderv1.RecommendResponse, error) {
+	ctx, span := tracer.Start(ctx, "Recommend", trace.WithAttributes(
+		attribute.String("api.version", "grpc"),
+		attribute.Bool("catalogue.cached", s.cache != nil),
+	))
+	defer span.End()
+
+	limit := int(req.GetLimit())
+	if limit == 0 {
+		limit = defaultRecommendLimit
+	}
+	if limit < 1 || limit > maxRecommendLimit {
+		return nil, grpcError(CodeInvalidRequest, fmt.Sprintf("limit must be from 1 to %d", maxRecommendLimit))
+	}
+	if req.GetQuery() == "" && req.GetCriteria() == nil {
+		return nil, grpcError(CodeQueryRequired, "query or criteria is required")
+	}
+
+	var criteria QueryCriteria
+	if req.GetQuery() != "" {
+		styles, err := catalogueStyles(ctx, s.db, s.cache)
+		if err != nil {
+			logStoreError(ctx, "Error retrieving restaurant styles", err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			return nil, grpcError(storeErrorCode(err), "Error retrieving restaurant styles")
+		}
+		criteria = parseCriteria(ctx, req.GetQuery(), styles)
+	}
+	if err := applyCriteria(&criteria, req.GetCriteria()); err != nil {
+		return nil, grpcError(CodeInvalidRequest, err.Error())
+	}
+
+	// Requests with only structured criteria are logged as those criteria.
+	query := req.GetQuery()
+	if query == "" {
+		b, _ := json.Marshal(criteria)
+		query = string(b)
+	}
+	rec, err := answerRecommendation(ctx, s.db, s.cache, s.logs, query, criteria)
+	if err != nil {
+		logStoreError(ctx, "Error retrieving restaurants", err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, grpcError(storeErrorCode(err), "Error retrieving restaurants")
+	}
+
+	resp := &recommenderv1.RecommendResponse{
+		Criteria: criteriaProto(rec.criteria),
+		Total:    int32(len(rec.matches)),
+		Limit:    int32(limit),
+		Relaxed:  len(rec.alternatives) > 0,
+	}
+	for _, r := range rec.matches[:min(limit, len(rec.matches))] {
+		resp.Results = append(resp.Results, restaurantProto(r))
+	}
+	for _, alt := range rec.alternatives {
+		a := &recommenderv1.Alternative{Restaurant: restaurantProto(alt.Restaurant)}
+		for _, rl := range alt.Relaxed {
+			a.Relaxed = append(a.Relaxed, relaxationProtos[rl])
+		}
+		resp.Alternatives = append(resp.Alternatives, a)
+	}
+	return resp, nil
+}
+
+// GetRestaurant looks up a restaurant by its public ID.
+func (s *GRPCServer) GetRestaurant(ctx context.Context, req *recommenderv1.GetRestaurantRequest) (*recommenderv1.GetRestaurantResponse, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, grpcError(CodeInvalidRequest, fmt.Sprintf("invalid restaurant id %q", req.GetId()))
+	}
+	restaurant, err := getRestaurant(ctx, s.db, id.String())
+	if errors.Is(err, errRestaurantNotFound) {
+		return nil, grpcError(CodeRestaurantNotFound, "")
+	}
+	if err != nil {
+		logStoreError(ctx, "Error accessing restaurants", err)
+		return nil, grpcError(storeErrorCode(err), "")
+	}
+	return &recommenderv1.GetRestaurantResponse{Restaurant: restaurantProto(restaurant)}, nil
+}
+
+// applyCriteria overrides criteria with the fields set in c.
+func applyCriteria(criteria *QueryCriteria, c *recommenderv1.Criteria) error {
+	if c == nil {
+		return nil
+	}
+	if c.Style != "" {
+		criteria.Style = c.Style
+	}
+	if c.Vegetarian != nil {
+		criteria.Vegetarian = c.Vegetarian
+	}
+	if c.Delivers != nil {
+		criteria.Delivers = c.Delivers
+	}
+	if c.Parking != nil {
+		criteria.Parking = c.Parking
+	}
+	if c.Wifi != nil {
+		criteria.WiFi = c.Wifi
+	}
+	if c.Accessible != nil {
+		criteria.Accessible = c.Accessible
+	}
+	if c.OpenNow {
+		criteria.OpenNow = true
+	}
+	if c.OpenAt != "" {
+		if err := validateHour("open_at", c.OpenAt); err != nil {
+			return err
+		}
+		openAt, _ := parseTime(c.OpenAt)
+		criteria.OpenAt = &openAt
+	}
+	return nil
+}
+
+// criteriaProto converts criteria to their protobuf form.
+func criteriaProto(c QueryCriteria) *recommenderv1.Criteria {
+	out := &recommenderv1.Criteria{
+		Style:      c.Style,
+		Vegetarian: c.Vegetarian,
+		Delivers:   c.Delivers,
+		Parking:    c.Parking,
+		Wifi:       c.WiFi,
+		Accessible: c.Accessible,
+		OpenNow:    c.OpenNow,
+	}
+	if c.OpenAt != nil {
+		out.OpenAt = c.OpenAt.Format("15:04")
+	}
+	return out
+}
+
+// restaurantProto converts a restaurant to its protobuf form.
+func restaurantProto(r Restaurant) *recommenderv1.Restaurant {
+	return &recommenderv1.Restaurant{
+		Id:                   r.ID,
+		Name:                 r.Name,
+		Style:                r.Style,
+		Address:              r.Address,
+		OpenHour:             r.OpenHour,
+		CloseHour:            r.CloseHour,
+		Vegetarian:           r.Vegetarian,
+		Deliveries:           r.Deliveries,
+		Phone:                r.Phone,
+		Website:              r.Website,
+		Email:                r.Email,
+		SeatingCapacity:      int32(r.SeatingCapacity),
+		Parking:              r.Parking,
+		Wifi:                 r.WiFi,
+		WheelchairAccessible: r.WheelchairAccessible,
+	}
+}
+
+// requestIDMetadataKey carries the request ID in gRPC metadata.
+const requestIDMetadataKey = "x-request-id"
+
+// UnaryServerInterceptor gives each RPC a request ID, taken from the
+// x-request-id metadata or generated and echoed in the response header, and
+// writes an access log line like LogRequests.
+func UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(requestIDMetadataKey); len(ids) > 0 {
+			id = ids[0]
+		}
+	}
+	if !validRequestID(id) {
+		id = uuid.NewString()
+	}
+	ctx = WithRequestID(ctx, id)
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadataKey, id))
+
+	resp, err := handler(ctx, req)
+	slog.InfoContext(ctx, "RPC served",
+		"method", info.FullMethod,
+		"code", status.Code(err).String(),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return resp, err
+}
@@ -0,0 +1,198 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	recommenderv1 "github.com/kuhlman-labs/restaurant-recommender/proto/recommender/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// newGRPCClient serves the RecommenderService over an in-memory connection
+// and returns a client for it.
+func newGRPCClient(t *testing.T, db *sql.DB, cache *CatalogueCache) recommenderv1.RecommenderServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(UnaryServerInterceptor))
+	recommenderv1.RegisterRecommenderServiceServer(srv, NewGRPCServer(db, cache, nil))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial the gRPC server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return recommenderv1.NewRecommenderServiceClient(conn)
+}
+
+// errorReason returns the error code carried in a gRPC error's details.
+func errorReason(err error) ErrorCode {
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return ErrorCode(info.Reason)
+		}
+	}
+	return ""
+}
+
+// TestGRPCRecommend tests free-text and structured queries over gRPC.
+func TestGRPCRecommend(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	expectCatalogueQuery(mock)
+	client := newGRPCClient(t, db, NewCatalogueCache(db, time.Minute))
+	ctx := context.Background()
+
+	var header metadata.MD
+	resp, err := client.Recommend(ctx, &recommenderv1.RecommendRequest{Query: "italian", Limit: 1}, grpc.Header(&header))
+	if err != nil {
+		t.Fatalf("Recommend returned error: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Name != "Pizza Hut" || resp.Total != 2 || resp.Limit != 1 {
+		t.Errorf("Unexpected response %v", resp)
+	}
+	if resp.Criteria.GetStyle() != "Italian" {
+		t.Errorf("Expected style Italian, got %q", resp.Criteria.GetStyle())
+	}
+	if ids := header.Get(requestIDMetadataKey); len(ids) != 1 || !validRequestID(ids[0]) {
+		t.Errorf("Expected a request ID in the response header, got %v", ids)
+	}
+
+	// Structured criteria override the query.
+	resp, err = client.Recommend(ctx, &recommenderv1.RecommendRequest{
+		Query:    "italian with delivery",
+		Criteria: &recommenderv1.Criteria{Delivers: proto.Bool(false)},
+	})
+	if err != nil {
+		t.Fatalf("Recommend returned error: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Name != "Luigi's" || resp.Criteria.Delivers == nil || *resp.Criteria.Delivers {
+		t.Errorf("Unexpected response %v", resp)
+	}
+
+	// Criteria alone are enough, and alternatives come with their relaxations.
+	resp, err = client.Recommend(ctx, &recommenderv1.RecommendRequest{
+		Criteria: &recommenderv1.Criteria{Style: "Italian", OpenAt: "08:45"},
+	})
+	if err != nil {
+		t.Fatalf("Recommend returned error: %v", err)
+	}
+	if len(resp.Results) != 0 || !resp.Relaxed || len(resp.Alternatives) != 1 {
+		t.Fatalf("Unexpected response %v", resp)
+	}
+	alt := resp.Alternatives[0]
+	if alt.Restaurant.GetName() != "Pizza Hut" || len(alt.Relaxed) != 1 || alt.Relaxed[0] != recommenderv1.Relaxation_RELAXATION_OPEN_TIME {
+		t.Errorf("Unexpected alternative %v", alt)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
+
+// TestGRPCRecommend_Errors tests that invalid requests and store failures map
+// to gRPC status codes carrying the API's error codes.
+func TestGRPCRecommend_Errors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT DISTINCT style FROM restaurants").WillReturnError(context.DeadlineExceeded)
+	client := newGRPCClient(t, db, nil)
+
+	tests := []struct {
+		name string
+		req  *recommenderv1.RecommendRequest
+		code codes.Code
+		want ErrorCode
+	}{
+		{"empty", &recommenderv1.RecommendRequest{}, codes.InvalidArgument, CodeQueryRequired},
+		{"limit", &recommenderv1.RecommendRequest{Query: "pizza", Limit: 51}, codes.InvalidArgument, CodeInvalidRequest},
+		{"open_at", &recommenderv1.RecommendRequest{Criteria: &recommenderv1.Criteria{OpenAt: "6pm"}}, codes.InvalidArgument, CodeInvalidRequest},
+		{"store", &recommenderv1.RecommendRequest{Query: "pizza"}, codes.DeadlineExceeded, CodeStoreTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Recommend(context.Background(), tt.req)
+			if status.Code(err) != tt.code || errorReason(err) != tt.want {
+				t.Errorf("Expected %s (%s), got %v (%s)", tt.code, tt.want, err, errorReason(err))
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
+
+// TestGRPCGetRestaurant tests restaurant lookups over gRPC.
+func TestGRPCGetRestaurant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM restaurants WHERE publicId = @p1")).
+		WithArgs("3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70").
+		WillReturnRows(sqlmock.NewRows(restaurantRowColumns).
+			AddRow("3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70", "Test Restaurant", "Italian", "123 Main St", "09:00", "23:00", true, true,
+				"", "", "", 40, false, true, false))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM restaurants WHERE publicId = @p1")).
+		WithArgs("8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d").
+		WillReturnRows(sqlmock.NewRows(restaurantRowColumns))
+
+	client := newGRPCClient(t, db, nil)
+	ctx := context.Background()
+
+	// Request IDs sent by the client are echoed back.
+	var header metadata.MD
+	ctx = metadata.AppendToOutgoingContext(ctx, requestIDMetadataKey, "client-id-1")
+	resp, err := client.GetRestaurant(ctx, &recommenderv1.GetRestaurantRequest{Id: "3F2C1A9E-5B7D-4E8F-9A10-2B3C4D5E6F70"}, grpc.Header(&header))
+	if err != nil {
+		t.Fatalf("GetRestaurant returned error: %v", err)
+	}
+	restaurant := resp.GetRestaurant()
+	if restaurant.GetName() != "Test Restaurant" || restaurant.GetSeatingCapacity() != 40 || !restaurant.GetWifi() {
+		t.Errorf("Unexpected restaurant %v", resp)
+	}
+	if ids := header.Get(requestIDMetadataKey); len(ids) != 1 || ids[0] != "client-id-1" {
+		t.Errorf("Expected request ID client-id-1, got %v", ids)
+	}
+
+	_, err = client.GetRestaurant(ctx, &recommenderv1.GetRestaurantRequest{Id: "8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"})
+	if status.Code(err) != codes.NotFound || errorReason(err) != CodeRestaurantNotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+
+	_, err = client.GetRestaurant(ctx, &recommenderv1.GetRestaurantRequest{Id: "42"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
@@ -1,66 +1,272 @@
 package restaurantrecommender
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// recommendation is the outcome of a recommendation query, shared by every
+// version of the API. alternatives are only looked for when nothing matched.
+type recommendation struct {
+	query        string
+	criteria     QueryCriteria
+	matches      []Restaurant
+	alternatives []Alternative
+}
+
+// parseCriteria parses a free-text query against the catalogue's styles.
+func parseCriteria(ctx context.Context, query string, styles []string) QueryCriteria {
+	_, span := tracer.Start(ctx, "parseQuery")
+	defer span.End()
+	criteria := parseQuery(query, styles)
+	span.SetAttributes(criteriaAttributes(criteria)...)
+	return criteria
+}
+
+// answerRecommendation finds the restaurants matching the criteria, or
+// alternatives if there are none, records the result on the span in ctx and
+// in the metrics, and queues the query log. It is shared by every API.
+func answerRecommendation(ctx context.Context, db *sql.DB, cache *CatalogueCache, logs *QueryLogWriter, query string, criteria QueryCriteria) (recommendation, error) {
+	span := trace.SpanFromContext(ctx)
+	rec := recommendation{query: query, criteria: criteria}
+
+	var err error
+	rec.matches, err = matchingRestaurants(ctx, db, cache, criteria, time.Now())
+	if err != nil {
+		return recommendation{}, err
+	}
+
+	if len(rec.matches) > 0 {
+		recommendationsTotal.WithLabelValues(resultMatch).Inc()
+		span.SetAttributes(
+			attribute.String("recommendation.result", resultMatch),
+			attribute.String("restaurant.id", rec.matches[0].ID),
+		)
+		// Queue the query and response to be logged asynchronously.
+		logs.Log(ctx, query, Recommendation{RestaurantRecommendation: rec.matches[0]})
+		return rec, nil
+	}
+
+	// Suggestions are best effort: failing to find them still answers the
+	// query with no match.
+	rec.alternatives, err = findAlternatives(ctx, db, cache, criteria, time.Now())
+	if err != nil {
+		slog.WarnContext(ctx, "Error finding alternative restaurants", "err", err)
+	}
+	result := resultNoMatch
+	if len(rec.alternatives) > 0 {
+		result = resultRelaxed
+	}
+	recommendationsTotal.WithLabelValues(result).Inc()
+	span.SetAttributes(attribute.String("recommendation.result", result))
+	logs.Log(ctx, query, Recommendation{
+		RestaurantRecommendation: Restaurant{
+			Name:       "No match found",
+			Style:      "",
+			Address:    "",
+			OpenHour:   "",
+			CloseHour:  "",
+			Vegetarian: false,
+			Deliveries: false,
+		},
+		Alternatives: rec.alternatives,
+	})
+	return rec, nil
+}
+
+// recommend answers the recommendation query of an HTTP request. On failure
+// it writes the error response and returns false. ctx carries the calling
+// handler's span.
+func recommend(ctx context.Context, w http.ResponseWriter, r *http.Request, db *sql.DB, cache *CatalogueCache, logs *QueryLogWriter) (recommendation, bool) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Bool("catalogue.cached", cache != nil))
+
+	styles, err := catalogueStyles(ctx, db, cache)
+	if err != nil {
+		logStoreError(ctx, "Error retrieving restaurant styles", err)
+		span.SetStatus(codes.Error, err.Error())
+		writeError(w, r, storeErrorCode(err), "Error retrieving restaurant styles")
+		return recommendation{}, false
+	}
+
+	query := r.URL.Query().Get("query")
+	if query == "" {
+		writeError(w, r, CodeQueryRequired, "")
+		return recommendation{}, false
+	}
+
+	rec, err := answerRecommendation(ctx, db, cache, logs, query, parseCriteria(ctx, query, styles))
+	if err != nil {
+		logStoreError(ctx, "Error retrieving restaurants", err)
+		span.SetStatus(codes.Error, err.Error())
+		writeError(w, r, storeErrorCode(err), "Error retrieving restaurants")
+		return recommendation{}, false
+	}
+	return rec, true
+}
+
 // RecommendHandler returns a handler that has access to the db dependency.
-func RecommendHandler(db *sql.DB) http.HandlerFunc {
+// When cache is non-nil the catalogue is read from it instead of the database,
+// and when logs is non-nil every query and response is written to query_logs.
+// It serves /v1/recommend: the first match as a Recommendation, or a NO_MATCH
+// problem. The shape is frozen for existing clients.
+func RecommendHandler(db *sql.DB, cache *CatalogueCache, logs *QueryLogWriter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		styles, err := getRestaurantStyles(db)
-		if err != nil {
-			http.Error(w, "Error retrieving restaurant styles", http.StatusInternalServerError)
+		ctx, span := tracer.Start(r.Context(), "RecommendHandler",
+			trace.WithAttributes(attribute.String("api.version", "v1")))
+		defer span.End()
+
+		rec, ok := recommend(ctx, w, r, db, cache, logs)
+		if !ok {
 			return
 		}
-
-		queryParam := r.URL.Query().Get("query")
-		if queryParam == "" {
-			http.Error(w, "Query parameter is required", http.StatusBadRequest)
+		if len(rec.matches) == 0 {
+			problem := newProblem(r, CodeNoMatch, "")
+			problem.Criteria = &rec.criteria
+			problem.Alternatives = rec.alternatives
+			if len(rec.alternatives) > 0 {
+				problem.Detail = "No exact match; alternatives relax some of the criteria"
+			}
+			writeProblem(w, r, problem)
 			return
 		}
 
-		criteria := parseQuery(queryParam, styles)
-		now := time.Now()
-		restaurants, err := getRestaurants(db)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Recommendation{RestaurantRecommendation: rec.matches[0]})
+	}
+}
+
+// Limits on the number of /v2 recommendation results.
+const (
+	defaultRecommendLimit = 10
+	maxRecommendLimit     = 50
+)
+
+// RecommendationList is the /v2 recommendation response: every match up to
+// the limit, the alternatives suggested when nothing matched, and metadata
+// describing how the query was understood.
+type RecommendationList struct {
+	Results      []Restaurant       `json:"results"`
+	Alternatives []Alternative      `json:"alternatives,omitempty"`
+	Meta         RecommendationMeta `json:"meta"`
+}
+
+// RecommendationMeta describes a /v2 recommendation query and its results.
+type RecommendationMeta struct {
+	Query    string        `json:"query"`
+	Criteria QueryCriteria `json:"criteria"`
+	Total    int           `json:"total"` // matches before the limit was applied
+	Limit    int           `json:"limit"`
+	Relaxed  bool          `json:"relaxed"` // true when the results are alternatives
+}
+
+// recommendLimit parses the "limit" query parameter of a /v2 request.
+func recommendLimit(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return defaultRecommendLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > maxRecommendLimit {
+		return 0, fmt.Errorf("limit must be an integer from 1 to %d", maxRecommendLimit)
+	}
+	return limit, nil
+}
+
+// RecommendV2Handler returns the /v2/recommend handler. It answers with a
+// RecommendationList; a query nothing matches is not an error, but an empty
+// list with any alternatives found by relaxing the criteria.
+func RecommendV2Handler(db *sql.DB, cache *CatalogueCache, logs *QueryLogWriter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "RecommendV2Handler",
+			trace.WithAttributes(attribute.String("api.version", "v2")))
+		defer span.End()
+
+		limit, err := recommendLimit(r)
 		if err != nil {
-			http.Error(w, "Error retrieving restaurants", http.StatusInternalServerError)
+			writeError(w, r, CodeInvalidRequest, err.Error())
+			return
+		}
+		rec, ok := recommend(ctx, w, r, db, cache, logs)
+		if !ok {
 			return
 		}
 
-		var candidate Restaurant
-		found := false
-		for _, rest := range restaurants {
-			if restaurantMatchesCriteria(rest, criteria, now) {
-				candidate = rest
-				found = true
-				break
-			}
+		writeJSON(w, r, http.StatusOK, rec.list(limit))
+	}
+}
+
+// list returns the first limit matches and the alternatives as a /v2 list.
+func (rec recommendation) list(limit int) RecommendationList {
+	list := RecommendationList{
+		Results:      rec.matches[:min(limit, len(rec.matches))],
+		Alternatives: rec.alternatives,
+		Meta: RecommendationMeta{
+			Query:    rec.query,
+			Criteria: rec.criteria,
+			Total:    len(rec.matches),
+			Limit:    limit,
+			Relaxed:  len(rec.alternatives) > 0,
+		},
+	}
+	if list.Results == nil {
+		list.Results = []Restaurant{}
+	}
+	return list
+}
+
+// criteriaAttributes describes the parsed criteria on a span. The raw query
+// is left out, as it is free text from the user.
+func criteriaAttributes(c QueryCriteria) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("criteria.style", c.Style),
+		attribute.Bool("criteria.open_now", c.OpenNow),
+	}
+	flags := []struct {
+		key   string
+		value *bool
+	}{
+		{"criteria.vegetarian", c.Vegetarian},
+		{"criteria.delivers", c.Delivers},
+		{"criteria.parking", c.Parking},
+		{"criteria.wifi", c.WiFi},
+		{"criteria.accessible", c.Accessible},
+	}
+	for _, f := range flags {
+		if f.value != nil {
+			attrs = append(attrs, attribute.Bool(f.key, *f.value))
 		}
+	}
+	if c.OpenAt != nil {
+		attrs = append(attrs, attribute.String("criteria.open_at", c.OpenAt.Format("15:04")))
+	}
+	return attrs
+}
 
-		if !found {
-			http.Error(w, "No restaurant found matching the criteria", http.StatusNotFound)
-			go logQueryAndResponse(queryParam, Recommendation{
-				RestaurantRecommendation: Restaurant{
-					Name:       "No match found",
-					Style:      "",
-					Address:    "",
-					OpenHour:   "",
-					CloseHour:  "",
-					Vegetarian: false,
-					Deliveries: false,
-				},
-			}, db)
+// GetRestaurantHandler returns a handler that looks up a restaurant by the public ID
+// included in recommendations.
+func GetRestaurantHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := restaurantID(r)
+		if err != nil {
+			writeError(w, r, CodeInvalidRequest, err.Error())
 			return
 		}
-
-		response := Recommendation{RestaurantRecommendation: candidate}
-		// Log the query and response asynchronously.
-		go logQueryAndResponse(queryParam, response, db)
-
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(response)
+		restaurant, err := getRestaurant(r.Context(), db, id)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+		writeJSON(w, r, http.StatusOK, restaurant)
 	}
 }
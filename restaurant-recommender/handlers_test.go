@@ -1,10 +1,16 @@
 package restaurantrecommender
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 )
@@ -24,7 +30,7 @@ func TestRecommendHandler_NoQuery(t *testing.T) {
 	mock.ExpectQuery("SELECT DISTINCT style FROM restaurants").
 		WillReturnRows(rows)
 
-	handler := RecommendHandler(db)
+	handler := RecommendHandler(db, nil, nil)
 	req := httptest.NewRequest(http.MethodGet, "/recommend", nil)
 	rec := httptest.NewRecorder()
 
@@ -33,6 +39,9 @@ func TestRecommendHandler_NoQuery(t *testing.T) {
 	if rec.Code != http.StatusBadRequest {
 		t.Errorf("Expected status 400, got %d", rec.Code)
 	}
+	if problem := decodeError(t, rec); problem.Code != CodeQueryRequired || problem.Status != http.StatusBadRequest {
+		t.Errorf("Unexpected problem %+v", problem)
+	}
 
 	// Optionally check that all expected queries were met.
 	if err := mock.ExpectationsWereMet(); err != nil {
@@ -56,19 +65,22 @@ func TestRecommendHandler_ValidQuery(t *testing.T) {
 	mock.ExpectQuery("SELECT DISTINCT style FROM restaurants").
 		WillReturnRows(stylesRows)
 
-	// Expect the SQL query to retrieve restaurant records.
+	// Expect the SQL query to retrieve the restaurants matching the parsed criteria.
 	restaurantRows := sqlmock.NewRows([]string{
-		"name", "style", "address", "openHour", "closeHour", "vegetarian", "deliveries",
+		"id", "name", "style", "address", "openHour", "closeHour", "vegetarian", "deliveries",
+		"phone", "website", "email", "seatingCapacity", "parking", "wifi", "wheelchairAccessible",
 	}).
-		AddRow("Test Restaurant", "Italian", "123 Main St", "09:00", "23:00", true, true)
-	mock.ExpectQuery("SELECT name, style, address, openHour, closeHour, vegetarian, deliveries FROM restaurants").
+		AddRow("3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70", "Test Restaurant", "Italian", "123 Main St", "09:00", "23:00", true, true,
+			"+1 555 0199", "https://test.example", "test@test.example", 50, true, true, true)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT LOWER(CONVERT(NVARCHAR(36), publicId)), name, style, address, openHour, closeHour, vegetarian, deliveries, phone, website, email, seatingCapacity, parking, wifi, wheelchairAccessible FROM restaurants WHERE style = @p1 ORDER BY id")).
+		WithArgs("Italian").
 		WillReturnRows(restaurantRows)
 
 	// Create a valid GET request with query parameter.
 	req := httptest.NewRequest(http.MethodGet, "/recommend?query=Italian", nil)
 	rec := httptest.NewRecorder()
 
-	handler := RecommendHandler(db)
+	handler := RecommendHandler(db, nil, nil)
 	handler(rec, req)
 
 	// Check that we received a successful response.
@@ -82,13 +94,287 @@ func TestRecommendHandler_ValidQuery(t *testing.T) {
 		t.Errorf("Error unmarshalling response JSON: %v", err)
 	}
 
-	// Verify that the expected restaurant is returned.
+	// Verify that the expected restaurant is returned with its public ID.
+	if resp.RestaurantRecommendation.ID != "3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70" {
+		t.Errorf("Expected id %s, got %s", "3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70", resp.RestaurantRecommendation.ID)
+	}
 	if resp.RestaurantRecommendation.Name != "Test Restaurant" {
 		t.Errorf("Expected restaurant 'Test Restaurant', got %s", resp.RestaurantRecommendation.Name)
 	}
+	if resp.RestaurantRecommendation.Phone != "+1 555 0199" {
+		t.Errorf("Expected phone '+1 555 0199', got %s", resp.RestaurantRecommendation.Phone)
+	}
 
 	// Ensure all expected SQL queries were executed.
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Unmet SQL expectations: %v", err)
 	}
 }
+
+// TestRecommendHandler_StoreErrors tests that database failures map to 503 and
+// timeouts to 504.
+func TestRecommendHandler_StoreErrors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT DISTINCT style FROM restaurants").
+		WillReturnError(errors.New("connection refused"))
+	mock.ExpectQuery("SELECT DISTINCT style FROM restaurants").
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"style"}))
+
+	handler := RecommendHandler(db, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/recommend?query=Italian", nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", rec.Code)
+	}
+	if problem := decodeError(t, rec); problem.Code != CodeStoreUnavailable {
+		t.Errorf("Expected code %s, got %s", CodeStoreUnavailable, problem.Code)
+	}
+
+	// The request's own deadline expires while the query is running.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req = httptest.NewRequest(http.MethodGet, "/recommend?query=Italian", nil).WithContext(ctx)
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected status 504, got %d", rec.Code)
+	}
+	if problem := decodeError(t, rec); problem.Code != CodeStoreTimeout {
+		t.Errorf("Expected code %s, got %s", CodeStoreTimeout, problem.Code)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
+
+// TestRecommendHandler_Cached tests that recommendations are served from the
+// cache, filtering in memory, without per-request queries.
+func TestRecommendHandler_Cached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	// A single catalogue load serves both requests.
+	expectCatalogueQuery(mock)
+	handler := RecommendHandler(db, NewCatalogueCache(db, time.Minute), nil)
+
+	for _, query := range []string{"vegetarian italian", "italian open at 1pm"} {
+		req := httptest.NewRequest(http.MethodGet, "/recommend?query="+url.QueryEscape(query), nil)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%q: expected status OK, got %d", query, rec.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/recommend?query=vegetarian+italian", nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	var resp Recommendation
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Error unmarshalling response JSON: %v", err)
+	}
+	if resp.RestaurantRecommendation.Name != "Pizza Hut" {
+		t.Errorf("Expected restaurant 'Pizza Hut', got %s", resp.RestaurantRecommendation.Name)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
+
+// TestRecommendHandler_NoMatch tests that a query nothing matches returns a
+// NO_MATCH problem carrying the parsed criteria.
+func TestRecommendHandler_NoMatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	expectCatalogueQuery(mock)
+	handler := checkContract(t, RecommendHandler(db, NewCatalogueCache(db, time.Minute), nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/recommend?query="+url.QueryEscape("vegetarian italian open at 8am"), nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("Expected status 404, got %d", rec.Code)
+	}
+
+	problem := decodeError(t, rec)
+	if problem.Code != CodeNoMatch || problem.Instance != "/recommend" {
+		t.Errorf("Unexpected problem %+v", problem)
+	}
+	var body struct {
+		Criteria map[string]any `json:"criteria"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Error unmarshalling response JSON: %v", err)
+	}
+	want := map[string]any{"style": "Italian", "vegetarian": true, "openAt": "08:00"}
+	if !reflect.DeepEqual(body.Criteria, want) {
+		t.Errorf("Expected criteria %v, got %v", want, body.Criteria)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
+
+// TestRecommendHandler_Alternatives tests that a query with no exact match
+// suggests alternatives marked with the constraints they relax.
+func TestRecommendHandler_Alternatives(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	expectCatalogueQuery(mock)
+	handler := checkContract(t, RecommendHandler(db, NewCatalogueCache(db, time.Minute), nil))
+
+	// Pizza Hut opens at 09:00, within 30 minutes of the requested time.
+	req := httptest.NewRequest(http.MethodGet, "/recommend?query="+url.QueryEscape("italian open at 8:45am"), nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("Expected status 404, got %d", rec.Code)
+	}
+
+	problem := decodeError(t, rec)
+	if problem.Code != CodeNoMatch {
+		t.Errorf("Expected code %s, got %s", CodeNoMatch, problem.Code)
+	}
+	if len(problem.Alternatives) != 1 {
+		t.Fatalf("Expected one alternative, got %+v", problem.Alternatives)
+	}
+	alt := problem.Alternatives[0]
+	if alt.Name != "Pizza Hut" || !reflect.DeepEqual(alt.Relaxed, []Relaxation{RelaxOpenTime}) {
+		t.Errorf("Unexpected alternative %+v", alt)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
+
+// TestRecommendV2Handler tests the /v2 list of results and its metadata.
+func TestRecommendV2Handler(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	expectCatalogueQuery(mock)
+	handler := checkContract(t, RecommendV2Handler(db, NewCatalogueCache(db, time.Minute), nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/recommend?limit=1&query=italian", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status OK, got %d", rec.Code)
+	}
+	var list RecommendationList
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Error unmarshalling response JSON: %v", err)
+	}
+	if len(list.Results) != 1 || list.Results[0].Name != "Pizza Hut" {
+		t.Errorf("Expected only Pizza Hut, got %+v", list.Results)
+	}
+	if list.Meta.Total != 2 || list.Meta.Limit != 1 || list.Meta.Relaxed || list.Meta.Criteria.Style != "Italian" {
+		t.Errorf("Unexpected metadata %+v", list.Meta)
+	}
+
+	// Nothing matching is an empty list, with alternatives where there are any.
+	req = httptest.NewRequest(http.MethodGet, "/v2/recommend?query="+url.QueryEscape("italian open at 8:45am"), nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status OK, got %d", rec.Code)
+	}
+	list = RecommendationList{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Error unmarshalling response JSON: %v", err)
+	}
+	if len(list.Results) != 0 || list.Meta.Total != 0 || !list.Meta.Relaxed || list.Meta.Limit != defaultRecommendLimit {
+		t.Errorf("Unexpected response %+v", list)
+	}
+	if len(list.Alternatives) != 1 || list.Alternatives[0].Name != "Pizza Hut" {
+		t.Errorf("Expected Pizza Hut as an alternative, got %+v", list.Alternatives)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
+
+// TestGetRestaurantHandler tests looking up a restaurant by its public ID.
+func TestGetRestaurantHandler(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM restaurants WHERE publicId = @p1")).
+		WithArgs("3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70").
+		WillReturnRows(sqlmock.NewRows(restaurantRowColumns).
+			AddRow("3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70", "Test Restaurant", "Italian", "123 Main St", "09:00", "23:00", true, true,
+				"", "", "", 0, false, false, false))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM restaurants WHERE publicId = @p1")).
+		WithArgs("8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d").
+		WillReturnRows(sqlmock.NewRows(restaurantRowColumns))
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /restaurants/{id}", GetRestaurantHandler(db))
+
+	// Upper-case IDs are normalised before the lookup.
+	req := httptest.NewRequest(http.MethodGet, "/restaurants/3F2C1A9E-5B7D-4E8F-9A10-2B3C4D5E6F70", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var restaurant Restaurant
+	if err := json.Unmarshal(rec.Body.Bytes(), &restaurant); err != nil {
+		t.Fatalf("Error unmarshalling response JSON: %v", err)
+	}
+	if restaurant.ID != "3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70" {
+		t.Errorf("Expected id %s, got %s", "3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70", restaurant.ID)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/restaurants/8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rec.Code)
+	}
+	if problem := decodeError(t, rec); problem.Code != CodeRestaurantNotFound || problem.Instance != "/restaurants/8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d" {
+		t.Errorf("Unexpected problem %+v", problem)
+	}
+
+	// Malformed IDs are rejected without querying the database.
+	req = httptest.NewRequest(http.MethodGet, "/restaurants/7", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a malformed id, got %d", rec.Code)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"sync/atomic"
+)
+
+// Health check statuses.
+const (
+	healthOK          = "ok"
+	healthUnavailable = "unavailable"
+)
+
+// readinessTables are the tables the service must be able to query to serve traffic.
+var readinessTables = []string{"restaurants", "query_logs"}
+
+// HealthCheck is the result of one readiness check. The endpoint is
+// unauthenticated, so Error is a fixed description; the underlying error,
+// which can name the database host, login or TLS setup, is only logged.
+type HealthCheck struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// HealthResponse is the body of the health and readiness endpoints.
+type HealthResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+}
+
+// Readiness tracks whether the service is accepting traffic. The zero value is ready.
+type Readiness struct {
+	draining atomic.Bool
+}
+
+// Drain marks the service as shutting down, so readiness checks fail and
+// load balancers stop sending it new requests.
+func (r *Readiness) Drain() {
+	r.draining.Store(true)
+}
+
+// Draining reports whether Drain has been called.
+func (r *Readiness) Draining() bool {
+	return r.draining.Load()
+}
+
+// LivenessHandler reports that the process is up. It does not touch the
+// database, so a database outage does not get the process restarted.
+func LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, r, http.StatusOK, HealthResponse{Status: healthOK})
+	}
+}
+
+// ReadinessHandler reports whether the service can serve traffic: it is not
+// shutting down, the database is reachable and its tables are queryable.
+func ReadinessHandler(db *sql.DB, readiness *Readiness) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if readiness.Draining() {
+			writeJSON(w, r, http.StatusServiceUnavailable, HealthResponse{
+				Status: healthUnavailable,
+				Checks: map[string]HealthCheck{"shutdown": {Status: healthUnavailable, Error: "service is shutting down"}},
+			})
+			return
+		}
+
+		resp := HealthResponse{Status: healthOK, Checks: map[string]HealthCheck{}}
+		record := func(name, failure string, err error) {
+			check := HealthCheck{Status: healthOK}
+			if err != nil {
+				logStoreError(r.Context(), "Readiness check failed", err, "check", name)
+				check = HealthCheck{Status: healthUnavailable, Error: failure}
+				resp.Status = healthUnavailable
+			}
+			resp.Checks[name] = check
+		}
+
+		record("database", "database unreachable", pingDatabase(r.Context(), db))
+		for _, table := range readinessTables {
+			record(table, "table not queryable", checkTable(r.Context(), db, table))
+		}
+
+		status := http.StatusOK
+		if resp.Status != healthOK {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, r, status, resp)
+	}
+}
+
+// pingDatabase checks that a database connection can be established.
+func pingDatabase(ctx context.Context, db *sql.DB) (err error) {
+	ctx, finish := withQueryTimeout(ctx, "pingDatabase")
+	defer finish(&err)
+
+	return db.PingContext(ctx)
+}
+
+// checkTable checks that table exists and can be read. table must be a
+// trusted identifier, as it is not parameterised.
+func checkTable(ctx context.Context, db *sql.DB, table string) (err error) {
+	ctx, finish := withQueryTimeout(ctx, "checkTable")
+	defer finish(&err)
+
+	var one int
+	err = db.QueryRowContext(ctx, "SELECT TOP (1) 1 FROM "+table).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		// An empty table is still queryable.
+		return nil
+	}
+	return err
+}
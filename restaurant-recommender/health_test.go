@@ -0,0 +1,132 @@
+package restaurantrecommender
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// decodeHealth decodes a health endpoint response body.
+func decodeHealth(t *testing.T, rr *httptest.ResponseRecorder) HealthResponse {
+	t.Helper()
+	var resp HealthResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response %q: %v", rr.Body.String(), err)
+	}
+	return resp
+}
+
+// TestLivenessHandler tests that liveness succeeds without touching the database.
+func TestLivenessHandler(t *testing.T) {
+	rr := httptest.NewRecorder()
+	LivenessHandler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rr.Code)
+	}
+	if resp := decodeHealth(t, rr); resp.Status != "ok" {
+		t.Errorf("expected status ok, got %q", resp.Status)
+	}
+}
+
+// TestReadinessHandler tests readiness when the database and tables are available.
+func TestReadinessHandler(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectPing()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT TOP (1) 1 FROM restaurants")).
+		WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(1))
+	// An empty query_logs table is still ready.
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT TOP (1) 1 FROM query_logs")).
+		WillReturnRows(sqlmock.NewRows([]string{""}))
+
+	rr := httptest.NewRecorder()
+	ReadinessHandler(db, &Readiness{}).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	resp := decodeHealth(t, rr)
+	for _, name := range []string{"database", "restaurants", "query_logs"} {
+		if resp.Checks[name].Status != "ok" {
+			t.Errorf("expected %s check ok, got %+v", name, resp.Checks[name])
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// TestReadinessHandler_Unavailable tests that a failing check is reported
+// without the underlying error, which may describe the database.
+func TestReadinessHandler_Unavailable(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectPing()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT TOP (1) 1 FROM restaurants")).
+		WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT TOP (1) 1 FROM query_logs")).
+		WillReturnError(errors.New("login failed for user 'app' on sql-prod.example.net"))
+
+	rr := httptest.NewRecorder()
+	ReadinessHandler(db, &Readiness{}).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", rr.Code)
+	}
+	if body := rr.Body.String(); strings.Contains(body, "sql-prod") || strings.Contains(body, "login failed") {
+		t.Errorf("expected the database error not to be exposed, got %s", body)
+	}
+	resp := decodeHealth(t, rr)
+	if resp.Status != "unavailable" {
+		t.Errorf("expected status unavailable, got %q", resp.Status)
+	}
+	if got := resp.Checks["query_logs"]; got.Status != "unavailable" || got.Error != "table not queryable" {
+		t.Errorf("unexpected query_logs check: %+v", got)
+	}
+	if got := resp.Checks["restaurants"]; got.Status != "ok" {
+		t.Errorf("expected restaurants check ok, got %+v", got)
+	}
+}
+
+// TestReadinessHandler_Draining tests that readiness fails once shutdown starts.
+func TestReadinessHandler_Draining(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	readiness := &Readiness{}
+	readiness.Drain()
+
+	rr := httptest.NewRecorder()
+	ReadinessHandler(db, readiness).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", rr.Code)
+	}
+	if resp := decodeHealth(t, rr); resp.Checks["shutdown"].Status != "unavailable" {
+		t.Errorf("expected shutdown check to fail, got %+v", resp.Checks)
+	}
+
+	// No database calls are made while draining.
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,305 @@
+package restaurantrecommender
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Supported bulk import formats.
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatJSON = "json"
+)
+
+// Import row actions reported in an ImportRowResult.
+const (
+	importCreated = "created"
+	importUpdated = "updated"
+	importInvalid = "invalid"
+	importFailed  = "failed"
+)
+
+// ImportRowResult describes what happened to a single imported row. Line is the
+// input line, or for OpenStreetMap imports the position of the element, and
+// Source names the OSM element a row came from.
+type ImportRowResult struct {
+	Line   int    `json:"line"`
+	Source string `json:"source,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Action string `json:"action"`
+	ID     string `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportReport summarises a bulk import. A real import is written in a single
+// transaction: if a row fails to be stored, the import stops at that row and
+// is rolled back, so nothing is written, and the rows before it show what
+// would have been done.
+type ImportReport struct {
+	DryRun     bool              `json:"dryRun"`
+	RolledBack bool              `json:"rolledBack,omitempty"`
+	Created    int               `json:"created"`
+	Updated    int               `json:"updated"`
+	Invalid    int               `json:"invalid"`
+	Failed     int               `json:"failed"`
+	Rows       []ImportRowResult `json:"rows"`
+}
+
+// errImportStore wraps failures to begin or commit an import's transaction.
+var errImportStore = errors.New("import transaction failed")
+
+// OK reports whether every row was imported (or would be, for a dry run).
+func (r ImportReport) OK() bool {
+	return r.Invalid == 0 && r.Failed == 0
+}
+
+// importRow is a decoded input row, or the error that prevented decoding it.
+// source optionally identifies where the row came from, such as an OSM node.
+type importRow struct {
+	line       int
+	source     string
+	restaurant Restaurant
+	err        error
+}
+
+// ImportRestaurants reads restaurants in the given format, validates every row
+// and upserts the valid ones by name and address, in a single transaction.
+// Invalid rows are reported and skipped. With dryRun set nothing is written,
+// but the report still shows whether each row would be created or updated.
+// Errors beginning or committing the transaction wrap errImportStore.
+func ImportRestaurants(ctx context.Context, db *sql.DB, r io.Reader, format string, dryRun bool) (ImportReport, error) {
+	rows, err := decodeImportRows(ctx, r, format)
+	if err != nil {
+		return ImportReport{}, err
+	}
+	if dryRun {
+		return upsertRestaurants(ctx, db, rows, true), nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return ImportReport{}, fmt.Errorf("%w: %w", errImportStore, err)
+	}
+	report := upsertRestaurants(ctx, tx, rows, false)
+	if report.RolledBack {
+		if err := tx.Rollback(); err != nil {
+			return report, fmt.Errorf("%w: %w", errImportStore, err)
+		}
+		return report, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return ImportReport{}, fmt.Errorf("%w: %w", errImportStore, err)
+	}
+	return report, nil
+}
+
+// upsertRestaurants validates and stores decoded rows, recording the outcome
+// of each. Outside a dry run it stops at the first row that fails to be
+// stored and marks the report RolledBack, for the caller to roll back.
+func upsertRestaurants(ctx context.Context, db queryer, rows []importRow, dryRun bool) ImportReport {
+	report := ImportReport{DryRun: dryRun, Rows: make([]ImportRowResult, 0, len(rows))}
+	// seen tracks rows "created" earlier in a dry run, which never reach the database.
+	seen := make(map[string]bool)
+
+	for _, row := range rows {
+		result := ImportRowResult{Line: row.line, Source: row.source, Name: row.restaurant.Name}
+		err := row.err
+		if err == nil {
+			err = validateRestaurant(row.restaurant)
+		}
+		if err != nil {
+			result.Action = importInvalid
+			result.Error = err.Error()
+			report.Invalid++
+			report.Rows = append(report.Rows, result)
+			continue
+		}
+
+		action, id, err := importRestaurant(ctx, db, row.restaurant, dryRun, seen)
+		if err != nil {
+			result.Action = importFailed
+			result.Error = err.Error()
+			report.Failed++
+			report.Rows = append(report.Rows, result)
+			if dryRun {
+				continue
+			}
+			// Nothing before this row is kept, so none of it counts as written.
+			report.RolledBack = true
+			report.Created, report.Updated = 0, 0
+			return report
+		}
+
+		result.Action = action
+		result.ID = id
+		if result.Action == importCreated {
+			report.Created++
+		} else {
+			report.Updated++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+	return report
+}
+
+// importRestaurant creates the restaurant, or updates the one with the same name
+// and address, returning the action taken and the restaurant's public ID.
+func importRestaurant(ctx context.Context, db queryer, r Restaurant, dryRun bool, seen map[string]bool) (string, string, error) {
+	id, err := findRestaurantID(ctx, db, r.Name, r.Address)
+	if err != nil && !errors.Is(err, errRestaurantNotFound) {
+		return "", "", err
+	}
+	exists := err == nil
+
+	key := strings.ToLower(r.Name) + "\x00" + strings.ToLower(r.Address)
+	switch {
+	case dryRun && (exists || seen[key]):
+		return importUpdated, id, nil
+	case dryRun:
+		seen[key] = true
+		return importCreated, "", nil
+	case exists:
+		r.ID = id
+		return importUpdated, id, updateRestaurant(ctx, db, r)
+	default:
+		created, err := createRestaurant(ctx, db, r)
+		return importCreated, created.ID, err
+	}
+}
+
+// decodeImportRows decodes all rows of the input in the given format.
+func decodeImportRows(ctx context.Context, r io.Reader, format string) ([]importRow, error) {
+	switch format {
+	case ImportFormatCSV:
+		return decodeCSVRows(r)
+	case ImportFormatJSON:
+		return decodeJSONRows(r)
+	case ImportFormatOverpass:
+		return decodeOverpassRows(r)
+	case ImportFormatOSMPBF:
+		return decodeOSMPBFRows(ctx, r)
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+// decodeJSONRows decodes one Restaurant JSON object per line, skipping blank lines.
+func decodeJSONRows(r io.Reader) ([]importRow, error) {
+	var rows []importRow
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRequestBody)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		row := importRow{line: line}
+		dec := json.NewDecoder(bytes.NewReader(text))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&row.restaurant); err != nil {
+			row.err = fmt.Errorf("invalid JSON: %w", err)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading JSON lines: %w", err)
+	}
+	return rows, nil
+}
+
+// csvFields maps CSV header names, which match the Restaurant JSON names, to setters.
+var csvFields = map[string]func(r *Restaurant, v string) error{
+	// Exports include the public ID, but imports match on name and address instead.
+	"id":        func(r *Restaurant, v string) error { return nil },
+	"name":      func(r *Restaurant, v string) error { r.Name = v; return nil },
+	"style":     func(r *Restaurant, v string) error { r.Style = v; return nil },
+	"address":   func(r *Restaurant, v string) error { r.Address = v; return nil },
+	"openHour":  func(r *Restaurant, v string) error { r.OpenHour = v; return nil },
+	"closeHour": func(r *Restaurant, v string) error { r.CloseHour = v; return nil },
+	"phone":     func(r *Restaurant, v string) error { r.Phone = v; return nil },
+	"website":   func(r *Restaurant, v string) error { r.Website = v; return nil },
+	"email":     func(r *Restaurant, v string) error { r.Email = v; return nil },
+	"seatingCapacity": func(r *Restaurant, v string) error {
+		if v == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(v)
+		r.SeatingCapacity = n
+		return err
+	},
+	"vegetarian":           boolField(func(r *Restaurant) *bool { return &r.Vegetarian }),
+	"deliveries":           boolField(func(r *Restaurant) *bool { return &r.Deliveries }),
+	"parking":              boolField(func(r *Restaurant) *bool { return &r.Parking }),
+	"wifi":                 boolField(func(r *Restaurant) *bool { return &r.WiFi }),
+	"wheelchairAccessible": boolField(func(r *Restaurant) *bool { return &r.WheelchairAccessible }),
+}
+
+// boolField returns a CSV setter for a boolean field; empty values mean false.
+func boolField(field func(r *Restaurant) *bool) func(r *Restaurant, v string) error {
+	return func(r *Restaurant, v string) error {
+		if v == "" {
+			return nil
+		}
+		b, err := strconv.ParseBool(v)
+		*field(r) = b
+		return err
+	}
+}
+
+// decodeCSVRows decodes a CSV file whose header row names Restaurant fields.
+func decodeCSVRows(r io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	for i, name := range header {
+		header[i] = strings.TrimSpace(name)
+		if _, ok := csvFields[header[i]]; !ok {
+			return nil, fmt.Errorf("unknown CSV column %q", header[i])
+		}
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("reading CSV: %w", err)
+			}
+			rows = append(rows, importRow{line: parseErr.StartLine, err: err})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		row := importRow{line: line}
+		if len(record) != len(header) {
+			row.err = fmt.Errorf("expected %d fields, got %d", len(header), len(record))
+			rows = append(rows, row)
+			continue
+		}
+		for i, value := range record {
+			if err := csvFields[header[i]](&row.restaurant, strings.TrimSpace(value)); err != nil {
+				row.err = fmt.Errorf("invalid %s %q", header[i], value)
+				break
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
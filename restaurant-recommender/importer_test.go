@@ -0,0 +1,232 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestDecodeCSVRows tests that CSV rows are decoded and malformed rows are reported.
+func TestDecodeCSVRows(t *testing.T) {
+	input := `name,style,address,openHour,closeHour,vegetarian,seatingCapacity
+Curry House,Indian,1 Spice Rd,11:00,23:30,true,30
+Bad Bool,Thai,2 Basil St,10:00,22:00,maybe,10
+Short Row,Thai
+`
+	rows, err := decodeCSVRows(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("decodeCSVRows returned error: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(rows))
+	}
+
+	first := rows[0]
+	if first.err != nil || first.line != 2 {
+		t.Errorf("unexpected first row: %+v", first)
+	}
+	if first.restaurant.Name != "Curry House" || !first.restaurant.Vegetarian || first.restaurant.SeatingCapacity != 30 {
+		t.Errorf("unexpected first restaurant: %+v", first.restaurant)
+	}
+	if rows[1].err == nil || !strings.Contains(rows[1].err.Error(), "vegetarian") {
+		t.Errorf("expected vegetarian error on line 3, got %v", rows[1].err)
+	}
+	if rows[2].err == nil || rows[2].line != 4 {
+		t.Errorf("expected field count error on line 4, got %+v", rows[2])
+	}
+
+	if _, err := decodeCSVRows(strings.NewReader("name,stars\n")); err == nil {
+		t.Error("expected error for unknown CSV column")
+	}
+}
+
+// TestDecodeJSONRows tests that JSON lines are decoded and blank lines skipped.
+func TestDecodeJSONRows(t *testing.T) {
+	input := `{"name":"Curry House","style":"Indian","openHour":"11:00","closeHour":"23:30"}
+
+{"name":"Broken"
+`
+	rows, err := decodeJSONRows(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("decodeJSONRows returned error: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[0].err != nil || rows[0].restaurant.Style != "Indian" {
+		t.Errorf("unexpected first row: %+v", rows[0])
+	}
+	if rows[1].err == nil || rows[1].line != 3 {
+		t.Errorf("expected JSON error on line 3, got %+v", rows[1])
+	}
+}
+
+// TestImportRestaurants tests that valid rows are upserted by name and address.
+func TestImportRestaurants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	lookup := regexp.QuoteMeta("FROM restaurants WHERE name = @p1 AND address = @p2")
+	mock.ExpectBegin()
+	mock.ExpectQuery(lookup).
+		WithArgs("Curry House", "1 Spice Rd").
+		WillReturnRows(sqlmock.NewRows([]string{"publicId"}))
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO restaurants")).
+		WillReturnRows(sqlmock.NewRows([]string{"publicId"}).AddRow("0b9f6c2e-1d3a-4c5b-8e7f-6a5b4c3d2e1f"))
+	mock.ExpectQuery(lookup).
+		WithArgs("Pizza Hut", "Wherever Street 99, Somewhere").
+		WillReturnRows(sqlmock.NewRows([]string{"publicId"}).AddRow("3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70"))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE restaurants SET")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	input := `name,style,address,openHour,closeHour
+Curry House,Indian,1 Spice Rd,11:00,23:30
+Pizza Hut,Italian,"Wherever Street 99, Somewhere",10:00,23:00
+No Hours,Thai,3 Lime Ave,,
+`
+	report, err := ImportRestaurants(context.Background(), db, strings.NewReader(input), ImportFormatCSV, false)
+	if err != nil {
+		t.Fatalf("ImportRestaurants returned error: %v", err)
+	}
+	if report.Created != 1 || report.Updated != 1 || report.Invalid != 1 || report.Failed != 0 {
+		t.Errorf("unexpected report totals: %+v", report)
+	}
+	if report.OK() {
+		t.Error("expected report with an invalid row not to be OK")
+	}
+	if got := report.Rows[0]; got.Action != importCreated || got.ID != "0b9f6c2e-1d3a-4c5b-8e7f-6a5b4c3d2e1f" {
+		t.Errorf("unexpected first row result: %+v", got)
+	}
+	if got := report.Rows[2]; got.Action != importInvalid || got.Line != 4 || got.Error == "" {
+		t.Errorf("unexpected third row result: %+v", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// TestImportRestaurants_RollBack tests that a row failing to be stored rolls
+// back the rows written before it and stops the import.
+func TestImportRestaurants_RollBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	lookup := regexp.QuoteMeta("FROM restaurants WHERE name = @p1 AND address = @p2")
+	mock.ExpectBegin()
+	mock.ExpectQuery(lookup).WillReturnRows(sqlmock.NewRows([]string{"publicId"}))
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO restaurants")).
+		WillReturnRows(sqlmock.NewRows([]string{"publicId"}).AddRow("0b9f6c2e-1d3a-4c5b-8e7f-6a5b4c3d2e1f"))
+	mock.ExpectQuery(lookup).WillReturnRows(sqlmock.NewRows([]string{"publicId"}))
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO restaurants")).WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	input := `name,style,address,openHour,closeHour
+Curry House,Indian,1 Spice Rd,11:00,23:30
+Pizza Hut,Italian,Wherever Street 99,10:00,23:00
+Thai Garden,Thai,3 Lime Ave,12:00,22:00
+`
+	report, err := ImportRestaurants(context.Background(), db, strings.NewReader(input), ImportFormatCSV, false)
+	if err != nil {
+		t.Fatalf("ImportRestaurants returned error: %v", err)
+	}
+	if !report.RolledBack || report.Created != 0 || report.Failed != 1 || report.OK() {
+		t.Errorf("expected a rolled back report with one failure: %+v", report)
+	}
+	if len(report.Rows) != 2 {
+		t.Fatalf("expected the report to stop at the failed row, got %+v", report.Rows)
+	}
+	if got := report.Rows[1]; got.Action != importFailed || got.Line != 3 || !strings.Contains(got.Error, "connection reset") {
+		t.Errorf("unexpected failed row result: %+v", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// TestImportRestaurants_DryRun tests that a dry run reports changes without writing them.
+func TestImportRestaurants_DryRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	lookup := regexp.QuoteMeta("FROM restaurants WHERE name = @p1 AND address = @p2")
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery(lookup).
+			WithArgs("Curry House", "1 Spice Rd").
+			WillReturnRows(sqlmock.NewRows([]string{"publicId"}))
+	}
+
+	row := `{"name":"Curry House","style":"Indian","address":"1 Spice Rd","openHour":"11:00","closeHour":"23:30"}`
+	report, err := ImportRestaurants(context.Background(), db, strings.NewReader(row+"\n"+row+"\n"), ImportFormatJSON, true)
+	if err != nil {
+		t.Fatalf("ImportRestaurants returned error: %v", err)
+	}
+	if !report.DryRun || report.Created != 1 || report.Updated != 1 {
+		t.Errorf("expected the repeated row to be reported as an update: %+v", report)
+	}
+
+	// No INSERT or UPDATE expectations should be made.
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// TestImportRestaurantsHandler tests the upload endpoint's format detection and errors.
+func TestImportRestaurantsHandler(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	handler := ImportRestaurantsHandler(db)
+
+	// A CSV upload with only invalid rows never touches the database.
+	req := httptest.NewRequest(http.MethodPost, "/restaurants/import?dryRun=true", strings.NewReader("name,style\nCurry House,\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"invalid":1`) {
+		t.Errorf("expected one invalid row, got %s", rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/restaurants/import?format=xml", strings.NewReader(""))
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unsupported format, got %d", rec.Code)
+	}
+
+	// A transaction that cannot be started is a store error, not a bad upload.
+	mock.ExpectBegin().WillReturnError(errors.New("connection refused"))
+	req = httptest.NewRequest(http.MethodPost, "/restaurants/import", strings.NewReader(`{"name":"Curry House"}`))
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 when the transaction cannot begin, got %d", rec.Code)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package restaurantrecommender
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Page sizes for browsing the catalogue.
+const (
+	defaultRestaurantsLimit = 20
+	maxRestaurantsLimit     = 100
+)
+
+// restaurantCursor marks where a page of a listing ends. It records the sort
+// it was made for, so it cannot be used to page through a different one.
+type restaurantCursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d,omitempty"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// newRestaurantCursor returns the cursor of the page ending at r.
+func newRestaurantCursor(r Restaurant, sort string, desc bool) *restaurantCursor {
+	values := map[string]string{"name": r.Name, "style": r.Style, "closeHour": r.CloseHour}
+	return &restaurantCursor{Sort: sort, Desc: desc, Value: values[sort], ID: r.ID}
+}
+
+// String encodes the cursor as an opaque URL-safe token.
+func (c *restaurantCursor) String() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// parseRestaurantCursor decodes a cursor made by String.
+func parseRestaurantCursor(token string) (*restaurantCursor, error) {
+	errInvalid := errors.New("invalid cursor")
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errInvalid
+	}
+	var c restaurantCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, errInvalid
+	}
+	if _, ok := restaurantSortColumns[c.Sort]; !ok {
+		return nil, errInvalid
+	}
+	if _, err := uuid.Parse(c.ID); err != nil {
+		return nil, errInvalid
+	}
+	return &c, nil
+}
+
+// RestaurantPage is a page of the restaurant listing. NextCursor is empty on
+// the last page.
+type RestaurantPage struct {
+	Results    []Restaurant `json:"results"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+	Limit      int          `json:"limit"`
+}
+
+// parseListSort reads a listing sort: a key of restaurantSortColumns, "-"
+// prefixed for descending.
+func parseListSort(v string) (sort string, desc bool, err error) {
+	sort, desc = strings.TrimPrefix(v, "-"), strings.HasPrefix(v, "-")
+	if _, ok := restaurantSortColumns[sort]; !ok {
+		return sort, desc, fmt.Errorf("sort must be name, style or closeHour, optionally prefixed with -, got %q", v)
+	}
+	return sort, desc, nil
+}
+
+// parseListCursor decodes a listing cursor and checks it was made for the
+// given sort.
+func parseListCursor(token, sort string, desc bool) (*restaurantCursor, error) {
+	cursor, err := parseRestaurantCursor(token)
+	if err != nil {
+		return nil, err
+	}
+	if cursor.Sort != sort || cursor.Desc != desc {
+		return nil, errors.New("cursor was made for a different sort")
+	}
+	return cursor, nil
+}
+
+// parseListOptions reads the listing parameters: limit, sort (a key of
+// restaurantSortColumns, "-" prefixed for descending), cursor, and filters
+// named like the QueryCriteria JSON fields.
+func parseListOptions(query url.Values) (listOptions, error) {
+	opts := listOptions{sort: "name", limit: defaultRestaurantsLimit}
+	var errs []error
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxRestaurantsLimit {
+			errs = append(errs, fmt.Errorf("limit must be an integer from 1 to %d", maxRestaurantsLimit))
+		}
+		opts.limit = limit
+	}
+	if v := query.Get("sort"); v != "" {
+		var err error
+		if opts.sort, opts.desc, err = parseListSort(v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if v := query.Get("cursor"); v != "" {
+		var err error
+		if opts.after, err = parseListCursor(v, opts.sort, opts.desc); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	opts.criteria.Style = query.Get("style")
+	flags := []struct {
+		name string
+		dst  **bool
+	}{
+		{"vegetarian", &opts.criteria.Vegetarian},
+		{"delivers", &opts.criteria.Delivers},
+		{"parking", &opts.criteria.Parking},
+		{"wifi", &opts.criteria.WiFi},
+		{"accessible", &opts.criteria.Accessible},
+	}
+	for _, f := range flags {
+		if v := query.Get(f.name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s must be true or false, got %q", f.name, v))
+				continue
+			}
+			*f.dst = &b
+		}
+	}
+	if v := query.Get("openNow"); v != "" {
+		openNow, err := strconv.ParseBool(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("openNow must be true or false, got %q", v))
+		}
+		opts.criteria.OpenNow = openNow
+	}
+	if v := query.Get("openAt"); v != "" {
+		if err := validateHour("openAt", v); err != nil {
+			errs = append(errs, err)
+		} else {
+			openAt, _ := parseTime(v)
+			opts.criteria.OpenAt = &openAt
+		}
+	}
+	return opts, errors.Join(errs...)
+}
+
+// ListRestaurantsHandler returns a handler that browses the catalogue a page
+// at a time, sorted and filtered in the database. The nextCursor of a page is
+// passed back as "cursor", with the same sort, for the page after it.
+func ListRestaurantsHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		opts, err := parseListOptions(r.URL.Query())
+		if err != nil {
+			writeError(w, r, CodeInvalidRequest, err.Error())
+			return
+		}
+		restaurants, next, err := listRestaurants(r.Context(), db, opts, time.Now())
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+		page := RestaurantPage{Results: restaurants, Limit: opts.limit}
+		if next != nil {
+			page.NextCursor = next.String()
+		}
+		writeJSON(w, r, http.StatusOK, page)
+	}
+}
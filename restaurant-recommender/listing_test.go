@@ -0,0 +1,123 @@
+package restaurantrecommender
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestParseListOptions tests the listing parameters and their errors.
+func TestParseListOptions(t *testing.T) {
+	opts, err := parseListOptions(url.Values{})
+	if err != nil || opts.sort != "name" || opts.desc || opts.limit != defaultRestaurantsLimit || opts.after != nil {
+		t.Errorf("unexpected defaults %+v (err %v)", opts, err)
+	}
+
+	cursor := &restaurantCursor{Sort: "style", Desc: true, Value: "Italian", ID: "3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70"}
+	opts, err = parseListOptions(url.Values{
+		"sort":       {"-style"},
+		"limit":      {"5"},
+		"cursor":     {cursor.String()},
+		"style":      {"Italian"},
+		"vegetarian": {"true"},
+		"wifi":       {"false"},
+		"openAt":     {"18:30"},
+	})
+	if err != nil {
+		t.Fatalf("parseListOptions returned error: %v", err)
+	}
+	if opts.sort != "style" || !opts.desc || opts.limit != 5 || *opts.after != *cursor {
+		t.Errorf("unexpected options %+v", opts)
+	}
+	c := opts.criteria
+	if c.Style != "Italian" || c.Vegetarian == nil || !*c.Vegetarian || c.WiFi == nil || *c.WiFi ||
+		c.Delivers != nil || c.OpenAt == nil || c.OpenAt.Format("15:04") != "18:30" {
+		t.Errorf("unexpected criteria %+v", c)
+	}
+
+	_, err = parseListOptions(url.Values{
+		"sort":     {"rating"},
+		"limit":    {"1000"},
+		"parking":  {"maybe"},
+		"openAt":   {"6pm"},
+		"openNow":  {"soon"},
+		"cursor":   {"not-a-cursor"},
+		"delivers": {"1"},
+	})
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	for _, want := range []string{"limit must be", `sort must be`, `parking must be true or false, got "maybe"`,
+		"openAt must be a time", "openNow must be", "invalid cursor"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q, got:\n%v", want, err)
+		}
+	}
+
+	// A cursor only pages through the sort it was made for.
+	_, err = parseListOptions(url.Values{"cursor": {cursor.String()}})
+	if err == nil || !strings.Contains(err.Error(), "different sort") {
+		t.Errorf("expected a sort mismatch error, got %v", err)
+	}
+}
+
+// TestListRestaurantsHandler tests paging through the listing by cursor.
+func TestListRestaurantsHandler(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	// Each page reads one row more than its limit to find out whether
+	// another page follows.
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT TOP (@p2)")).
+		WithArgs(true, 3).
+		WillReturnRows(sqlmock.NewRows(restaurantRowColumns).
+			AddRow("3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70", "Green Garden", "Vegan", "2 Leaf Road", "08:00", "20:00", true, false,
+				"", "", "", 30, false, true, true).
+			AddRow("8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d", "Pizza Hut", "Italian", "Wherever Street 99", "09:00", "23:00", true, true,
+				"", "", "", 80, true, true, true).
+			AddRow("5d6e7f80-9a0b-4c1d-8e2f-3a4b5c6d7e8f", "Veggie Palace", "Indian", "3 Curry Lane", "11:00", "22:00", true, true,
+				"", "", "", 40, false, false, false))
+	mock.ExpectQuery(regexp.QuoteMeta("(name > @p2 OR (name = @p2 AND publicId > @p3))")).
+		WithArgs(true, "Pizza Hut", "8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d", 3).
+		WillReturnRows(sqlmock.NewRows(restaurantRowColumns).
+			AddRow("5d6e7f80-9a0b-4c1d-8e2f-3a4b5c6d7e8f", "Veggie Palace", "Indian", "3 Curry Lane", "11:00", "22:00", true, true,
+				"", "", "", 40, false, false, false))
+
+	handler := checkContract(t, ListRestaurantsHandler(db))
+	get := func(target string) RestaurantPage {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var page RestaurantPage
+		if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+			t.Fatalf("Error unmarshalling response JSON: %v", err)
+		}
+		return page
+	}
+
+	page := get("/restaurants?vegetarian=true&limit=2")
+	if len(page.Results) != 2 || page.Results[1].Name != "Pizza Hut" || page.Limit != 2 || page.NextCursor == "" {
+		t.Fatalf("Unexpected first page %+v", page)
+	}
+
+	page = get("/restaurants?vegetarian=true&limit=2&cursor=" + page.NextCursor)
+	if len(page.Results) != 1 || page.Results[0].Name != "Veggie Palace" || page.NextCursor != "" {
+		t.Errorf("Unexpected last page %+v", page)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
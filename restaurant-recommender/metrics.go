@@ -0,0 +1,161 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// metricsNamespace prefixes every metric the service exports.
+const metricsNamespace = "restaurant_recommender"
+
+// Recommendation results counted by recommendationsTotal.
+const (
+	resultMatch   = "match"
+	resultRelaxed = "relaxed" // no exact match, but alternatives were suggested
+	resultNoMatch = "no_match"
+)
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served, by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency, by route, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	httpRequestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database call latency, by data-layer function and outcome (ok, error or timeout).",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"function", "outcome"})
+
+	recommendationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "recommendations_total",
+		Help:      "Recommendation queries answered, by result (match, relaxed or no_match).",
+	}, []string{"result"})
+)
+
+// InstrumentHandler records request counts, latencies and in-flight requests
+// for next, labelled by the ServeMux pattern that matched the request. It also
+// names the request's span, if any, after that pattern.
+func InstrumentHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		httpRequestsInFlight.Inc()
+		defer httpRequestsInFlight.Dec()
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		// The ServeMux sets the pattern on the request as it routes it.
+		route := routeLabel(r.Pattern)
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(r.Method + " " + route)
+		span.SetAttributes(attribute.String("http.route", route))
+
+		labels := prometheus.Labels{
+			"route":  route,
+			"method": r.Method,
+			"status": strconv.Itoa(rec.status),
+		}
+		httpRequestsTotal.With(labels).Inc()
+		httpRequestDuration.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+// routeLabel turns a ServeMux pattern such as "GET /restaurants/{id}" into a
+// bounded route label, so arbitrary paths cannot create new series.
+func routeLabel(pattern string) string {
+	if pattern == "" {
+		return "unmatched"
+	}
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		return path
+	}
+	return pattern
+}
+
+// observeQuery records the duration of a data-layer call that started at start.
+func observeQuery(function string, start time.Time, err error) {
+	outcome := "ok"
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		outcome = "timeout"
+	case err != nil:
+		outcome = "error"
+	}
+	dbQueryDuration.WithLabelValues(function, outcome).Observe(time.Since(start).Seconds())
+}
+
+// RegisterMetrics exports the counters kept by the catalogue cache and the
+// query-log writer through reg. Either may be nil if it is disabled.
+func RegisterMetrics(reg prometheus.Registerer, cache *CatalogueCache, logs *QueryLogWriter) error {
+	var collectors []prometheus.Collector
+	counter := func(name, help string, value func() float64) {
+		collectors = append(collectors, prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: metricsNamespace, Name: name, Help: help,
+		}, value))
+	}
+
+	if cache != nil {
+		counter("cache_hits_total", "Catalogue reads served from the cache.",
+			func() float64 { return float64(cache.Stats().Hits) })
+		counter("cache_misses_total", "Catalogue reads that loaded from the database.",
+			func() float64 { return float64(cache.Stats().Misses) })
+		counter("cache_refreshes_total", "Catalogue loads from the database.",
+			func() float64 { return float64(cache.Stats().Refreshes) })
+		counter("cache_refresh_errors_total", "Catalogue loads that failed.",
+			func() float64 { return float64(cache.Stats().RefreshErrors) })
+	}
+
+	if logs != nil {
+		counter("query_log_enqueued_total", "Query-log entries queued for writing.",
+			func() float64 { return float64(logs.Stats().Enqueued) })
+		counter("query_log_written_total", "Query-log entries written to the database.",
+			func() float64 { return float64(logs.Stats().Written) })
+		counter("query_log_dropped_total", "Query-log entries dropped because the queue was full or closed.",
+			func() float64 { return float64(logs.Stats().Dropped) })
+		counter("query_log_failed_total", "Query-log entries whose insert failed.",
+			func() float64 { return float64(logs.Stats().Failed) })
+		collectors = append(collectors, prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "query_log_pending",
+			Help:      "Query-log entries queued and not yet written.",
+		}, func() float64 { return float64(logs.Stats().Pending) }))
+		collectors = append(collectors, prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "query_log_in_flight",
+			Help:      "Query-log entries taken off the queue and not yet written.",
+		}, func() float64 { return float64(logs.Stats().InFlight) }))
+	}
+
+	for _, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// TestInstrumentHandler tests that requests are counted by their matched route.
+func TestInstrumentHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /restaurants/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	handler := InstrumentHandler(mux)
+
+	found := httpRequestsTotal.WithLabelValues("/restaurants/{id}", "GET", "404")
+	unmatched := httpRequestsTotal.WithLabelValues("unmatched", "GET", "404")
+	beforeFound, beforeUnmatched := testutil.ToFloat64(found), testutil.ToFloat64(unmatched)
+
+	for _, path := range []string{"/restaurants/a", "/restaurants/b", "/nowhere"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if got := testutil.ToFloat64(found) - beforeFound; got != 2 {
+		t.Errorf("expected 2 requests for the route, got %v", got)
+	}
+	if got := testutil.ToFloat64(unmatched) - beforeUnmatched; got != 1 {
+		t.Errorf("expected 1 unmatched request, got %v", got)
+	}
+	if got := testutil.ToFloat64(httpRequestsInFlight); got != 0 {
+		t.Errorf("expected no requests in flight, got %v", got)
+	}
+}
+
+// sampleCount returns the number of observations in a histogram series.
+func sampleCount(t *testing.T, o prometheus.Observer) uint64 {
+	t.Helper()
+	var m dto.Metric
+	if err := o.(prometheus.Metric).Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+// TestWithQueryTimeout_Metrics tests that data-layer calls are timed by function and outcome.
+func TestWithQueryTimeout_Metrics(t *testing.T) {
+	call := func(ctx context.Context, callErr error) {
+		ctx, finish := withQueryTimeout(ctx, "testQuery")
+		err := callErr
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		finish(&err)
+	}
+
+	expired, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	call(context.Background(), nil)
+	call(context.Background(), nil)
+	call(context.Background(), errors.New("connection refused"))
+	call(expired, nil)
+
+	for outcome, want := range map[string]uint64{"ok": 2, "error": 1, "timeout": 1} {
+		if got := sampleCount(t, dbQueryDuration.WithLabelValues("testQuery", outcome)); got != want {
+			t.Errorf("expected %d %s observations, got %d", want, outcome, got)
+		}
+	}
+}
+
+// TestGetRestaurants_Metrics tests that a catalogue load is timed once, as
+// getRestaurants, rather than also as the streaming forEachRestaurant.
+func TestGetRestaurants_Metrics(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+	expectCatalogueQuery(mock)
+
+	loads := dbQueryDuration.WithLabelValues("getRestaurants", "ok")
+	streams := dbQueryDuration.WithLabelValues("forEachRestaurant", "ok")
+	beforeLoads, beforeStreams := sampleCount(t, loads), sampleCount(t, streams)
+	if _, err := getRestaurants(context.Background(), db); err != nil {
+		t.Fatalf("getRestaurants returned error: %v", err)
+	}
+	if got := sampleCount(t, loads) - beforeLoads; got != 1 {
+		t.Errorf("expected 1 getRestaurants observation, got %d", got)
+	}
+	if got := sampleCount(t, streams) - beforeStreams; got != 0 {
+		t.Errorf("expected no forEachRestaurant observations, got %d", got)
+	}
+}
+
+// TestRecommendationsTotal tests that matches and misses are counted.
+func TestRecommendationsTotal(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	expectCatalogueQuery(mock)
+	handler := RecommendHandler(db, NewCatalogueCache(db, time.Minute), nil)
+
+	match := recommendationsTotal.WithLabelValues(resultMatch)
+	noMatch := recommendationsTotal.WithLabelValues(resultNoMatch)
+	beforeMatch, beforeNoMatch := testutil.ToFloat64(match), testutil.ToFloat64(noMatch)
+
+	for _, query := range []string{"italian", "italian open at 3am"} {
+		req := httptest.NewRequest(http.MethodGet, "/recommend?query="+url.QueryEscape(query), nil)
+		handler(httptest.NewRecorder(), req)
+	}
+
+	if got := testutil.ToFloat64(match) - beforeMatch; got != 1 {
+		t.Errorf("expected 1 match, got %v", got)
+	}
+	if got := testutil.ToFloat64(noMatch) - beforeNoMatch; got != 1 {
+		t.Errorf("expected 1 miss, got %v", got)
+	}
+}
+
+// TestRegisterMetrics tests that cache and query-log counters are exported.
+func TestRegisterMetrics(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	expectCatalogueQuery(mock)
+	cache := NewCatalogueCache(db, time.Minute)
+	for range 3 {
+		if _, err := cache.Restaurants(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logs := NewQueryLogWriter(db, QueryLogConfig{FlushInterval: time.Hour})
+	logs.Close(context.Background())
+	logs.Log(context.Background(), "dropped", Recommendation{})
+
+	reg := prometheus.NewRegistry()
+	if err := RegisterMetrics(reg, cache, logs); err != nil {
+		t.Fatalf("RegisterMetrics returned error: %v", err)
+	}
+
+	expected := `
+# HELP restaurant_recommender_cache_hits_total Catalogue reads served from the cache.
+# TYPE restaurant_recommender_cache_hits_total counter
+restaurant_recommender_cache_hits_total 2
+# HELP restaurant_recommender_cache_misses_total Catalogue reads that loaded from the database.
+# TYPE restaurant_recommender_cache_misses_total counter
+restaurant_recommender_cache_misses_total 1
+# HELP restaurant_recommender_query_log_dropped_total Query-log entries dropped because the queue was full or closed.
+# TYPE restaurant_recommender_query_log_dropped_total counter
+restaurant_recommender_query_log_dropped_total 1
+# HELP restaurant_recommender_query_log_in_flight Query-log entries taken off the queue and not yet written.
+# TYPE restaurant_recommender_query_log_in_flight gauge
+restaurant_recommender_query_log_in_flight 0
+# HELP restaurant_recommender_query_log_pending Query-log entries queued and not yet written.
+# TYPE restaurant_recommender_query_log_pending gauge
+restaurant_recommender_query_log_pending 0
+`
+	err = testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"restaurant_recommender_cache_hits_total",
+		"restaurant_recommender_cache_misses_total",
+		"restaurant_recommender_query_log_dropped_total",
+		"restaurant_recommender_query_log_pending",
+		"restaurant_recommender_query_log_in_flight",
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// Disabled components are skipped.
+	if err := RegisterMetrics(prometheus.NewRegistry(), nil, nil); err != nil {
+		t.Errorf("expected nil components to be skipped, got %v", err)
+	}
+}
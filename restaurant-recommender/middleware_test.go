@@ -0,0 +1,72 @@
+package restaurantrecommender
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestLogRequests tests the access log line written for each request.
+func TestLogRequests(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil))))
+	defer slog.SetDefault(previous)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /restaurants/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {})
+	handler := RequestID(LogRequests(mux))
+
+	req := httptest.NewRequest(http.MethodGet, "/restaurants/abc", nil)
+	req.Header.Set(RequestIDHeader, "req-7")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	// Health probes are logged at debug level, below the handler's default.
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("expected one JSON log line, got %q: %v", buf.String(), err)
+	}
+	expected := map[string]any{
+		"msg":        "Request served",
+		"method":     "GET",
+		"route":      "/restaurants/{id}",
+		"path":       "/restaurants/abc",
+		"status":     float64(404),
+		"request_id": "req-7",
+	}
+	for key, want := range expected {
+		if line[key] != want {
+			t.Errorf("expected %s %v, got %v", key, want, line[key])
+		}
+	}
+}
+
+// TestDeprecated tests the deprecation headers added to responses.
+func TestDeprecated(t *testing.T) {
+	since := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	handler := Deprecated(since, "/v1/recommend", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Link", `</docs>; rel="help"`)
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/recommend", nil))
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("expected the wrapped handler's status, got %d", rec.Code)
+	}
+	if got, want := rec.Header().Get("Deprecation"), "@1792281600"; got != want {
+		t.Errorf("expected Deprecation %q, got %q", want, got)
+	}
+	links := rec.Header().Values("Link")
+	if len(links) != 2 || links[0] != `</v1/recommend>; rel="successor-version"` {
+		t.Errorf("expected a successor-version link alongside the handler's, got %q", links)
+	}
+}
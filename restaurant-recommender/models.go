@@ -1,28 +1,80 @@
 package restaurantrecommender
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
-// Restaurant represents a restaurant record.
+// Restaurant represents a restaurant record. ID is the stable, opaque public
+// identifier of the restaurant; the database's internal row ID is never exposed.
 type Restaurant struct {
-	Name       string `json:"name"`
-	Style      string `json:"style"`
-	Address    string `json:"address"`
-	OpenHour   string `json:"openHour"`
-	CloseHour  string `json:"closeHour"`
-	Vegetarian bool   `json:"vegetarian"`
-	Deliveries bool   `json:"deliveries"`
+	ID                   string `json:"id"`
+	Name                 string `json:"name"`
+	Style                string `json:"style"`
+	Address              string `json:"address"`
+	OpenHour             string `json:"openHour"`
+	CloseHour            string `json:"closeHour"`
+	Vegetarian           bool   `json:"vegetarian"`
+	Deliveries           bool   `json:"deliveries"`
+	Phone                string `json:"phone"`
+	Website              string `json:"website"`
+	Email                string `json:"email"`
+	SeatingCapacity      int    `json:"seatingCapacity"`
+	Parking              bool   `json:"parking"`
+	WiFi                 bool   `json:"wifi"`
+	WheelchairAccessible bool   `json:"wheelchairAccessible"`
 }
 
-// Recommendation wraps the restaurant recommendation in a JSON object.
+// Recommendation wraps the restaurant recommendation in a JSON object. When
+// nothing matched, query logs record the alternatives suggested instead.
 type Recommendation struct {
-	RestaurantRecommendation Restaurant `json:"restaurantRecommendation"`
+	RestaurantRecommendation Restaurant    `json:"restaurantRecommendation"`
+	Alternatives             []Alternative `json:"alternatives,omitempty"`
 }
 
 // QueryCriteria holds parsed filtering options from a natural language query.
 type QueryCriteria struct {
-	Style      string     // e.g., "Mexican", "Italian", etc.
-	Vegetarian *bool      // nil if not specified.
-	Delivers   *bool      // nil if not specified.
-	OpenNow    bool       // true if "open now" is mentioned.
-	OpenAt     *time.Time // specific time if provided (e.g., "open at 6pm")
+	Style      string     `json:"style,omitempty"`      // e.g., "Mexican", "Italian", etc.
+	Vegetarian *bool      `json:"vegetarian,omitempty"` // nil if not specified.
+	Delivers   *bool      `json:"delivers,omitempty"`   // nil if not specified.
+	Parking    *bool      `json:"parking,omitempty"`    // nil if not specified.
+	WiFi       *bool      `json:"wifi,omitempty"`       // nil if not specified.
+	Accessible *bool      `json:"accessible,omitempty"` // nil if not specified.
+	OpenNow    bool       `json:"openNow,omitempty"`    // true if "open now" is mentioned.
+	OpenAt     *time.Time `json:"openAt,omitempty"`     // specific time if provided (e.g., "open at 6pm")
+}
+
+// MarshalJSON encodes the criteria with OpenAt as a time of day ("18:00"),
+// since only its hour and minute are meaningful.
+func (c QueryCriteria) MarshalJSON() ([]byte, error) {
+	type criteria QueryCriteria
+	out := struct {
+		criteria
+		OpenAt string `json:"openAt,omitempty"`
+	}{criteria: criteria(c)}
+	if c.OpenAt != nil {
+		out.OpenAt = c.OpenAt.Format("15:04")
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes criteria encoded by MarshalJSON.
+func (c *QueryCriteria) UnmarshalJSON(data []byte) error {
+	type criteria QueryCriteria
+	var in struct {
+		criteria
+		OpenAt string `json:"openAt"`
+	}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*c = QueryCriteria(in.criteria)
+	if in.OpenAt != "" {
+		t, err := time.Parse("15:04", in.OpenAt)
+		if err != nil {
+			return err
+		}
+		c.OpenAt = &t
+	}
+	return nil
 }
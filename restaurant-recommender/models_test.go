@@ -45,3 +45,26 @@ func TestQueryCriteriaTimeField(t *testing.T) {
 		t.Errorf("Expected OpenAt time to be %v, got %v", now, *qc.OpenAt)
 	}
 }
+
+func TestQueryCriteriaJSON(t *testing.T) {
+	vegetarian := true
+	openAt := time.Date(0, 1, 1, 18, 30, 0, 0, time.UTC)
+	qc := QueryCriteria{Style: "Thai", Vegetarian: &vegetarian, OpenAt: &openAt}
+
+	data, err := json.Marshal(qc)
+	if err != nil {
+		t.Fatalf("Failed to marshal criteria: %v", err)
+	}
+	if want := `{"style":"Thai","vegetarian":true,"openAt":"18:30"}`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+
+	var decoded QueryCriteria
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal criteria: %v", err)
+	}
+	if decoded.Style != "Thai" || decoded.Vegetarian == nil || !*decoded.Vegetarian ||
+		decoded.OpenAt == nil || decoded.OpenAt.Format("15:04") != "18:30" {
+		t.Errorf("Unexpected decoded criteria %+v", decoded)
+	}
+}
@@ -0,0 +1,76 @@
+package restaurantrecommender
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// openAPISpec is the OpenAPI 3 description of the service's endpoints. Tests
+// check every handler's requests and responses against it.
+//
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+// LoadOpenAPI parses and validates the embedded OpenAPI document.
+func LoadOpenAPI() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(openAPISpec)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// openAPIJSON renders the OpenAPI document as JSON once, on first use.
+var openAPIJSON = sync.OnceValues(func() ([]byte, error) {
+	doc, err := LoadOpenAPI()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+})
+
+// OpenAPIHandler serves the OpenAPI document as JSON.
+func OpenAPIHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := openAPIJSON()
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Error loading the OpenAPI document", "err", err)
+			writeError(w, r, CodeInternal, "OpenAPI document unavailable")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}
+}
+
+// docsPage renders /openapi.json with Redoc, loaded from its CDN.
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>Restaurant Recommender API</title>
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+  <redoc spec-url="/openapi.json"></redoc>
+  <script src="https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`
+
+// DocsHandler serves a page documenting the API from its OpenAPI document.
+func DocsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(docsPage))
+	}
+}
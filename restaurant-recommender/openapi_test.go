@@ -0,0 +1,400 @@
+package restaurantrecommender
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+var (
+	openAPIRouterOnce sync.Once
+	openAPIRouter     routers.Router
+	openAPIRouterErr  error
+)
+
+// contractRouter returns a router finding operations in the OpenAPI document.
+func contractRouter(t *testing.T) routers.Router {
+	t.Helper()
+	openAPIRouterOnce.Do(func() {
+		// Non-JSON bodies are checked for their media type only.
+		for _, contentType := range []string{"application/x-ndjson", "application/xml", "text/html"} {
+			openapi3filter.RegisterBodyDecoder(contentType, openapi3filter.FileBodyDecoder)
+		}
+		var doc *openapi3.T
+		if doc, openAPIRouterErr = LoadOpenAPI(); openAPIRouterErr == nil {
+			openAPIRouter, openAPIRouterErr = gorillamux.NewRouter(doc)
+		}
+	})
+	if openAPIRouterErr != nil {
+		t.Fatalf("Error loading the OpenAPI document: %v", openAPIRouterErr)
+	}
+	return openAPIRouter
+}
+
+// contractOptions rejects undocumented response statuses and skips
+// authentication, which RequireToken enforces and the tests exercise directly.
+var contractOptions = &openapi3filter.Options{
+	AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+	IncludeResponseStatus: true,
+	MultiError:            true,
+}
+
+// checkContract wraps a handler so that every request it serves is checked
+// against the OpenAPI document: the route and status must be documented, the
+// response must match its schema, and requests the document rejects must be
+// rejected by the handler too.
+func checkContract(t *testing.T, next http.Handler) http.Handler {
+	t.Helper()
+	router := contractRouter(t)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body []byte
+		if r.Body != nil {
+			body, _ = io.ReadAll(r.Body)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		rec := httptest.NewRecorder()
+		next.ServeHTTP(rec, r)
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes())
+
+		req := r.Clone(context.Background())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		route, pathParams, err := router.FindRoute(req)
+		if err != nil {
+			t.Errorf("%s %s: undocumented route: %v", r.Method, r.URL, err)
+			return
+		}
+		input := &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    contractOptions,
+		}
+		if err := openapi3filter.ValidateRequest(context.Background(), input); err != nil && rec.Code < http.StatusBadRequest {
+			t.Errorf("%s %s: handler accepted a request the document rejects: %v", r.Method, r.URL, err)
+		}
+		err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 rec.Code,
+			Header:                 rec.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(rec.Body.Bytes())),
+			Options:                contractOptions,
+		})
+		if err != nil {
+			t.Errorf("%s %s: %d response does not match the document: %v\n%s", r.Method, r.URL, rec.Code, err, rec.Body)
+		}
+	})
+}
+
+// TestLoadOpenAPI tests that the embedded document is valid and that every
+// error code is documented.
+func TestLoadOpenAPI(t *testing.T) {
+	doc, err := LoadOpenAPI()
+	if err != nil {
+		t.Fatalf("LoadOpenAPI returned error: %v", err)
+	}
+	codes := doc.Components.Schemas["Problem"].Value.Properties["code"].Value.Enum
+	for code := range errorKinds {
+		documented := false
+		for _, c := range codes {
+			documented = documented || c == string(code)
+		}
+		if !documented {
+			t.Errorf("error code %s is not in the Problem schema", code)
+		}
+	}
+}
+
+// TestOpenAPIHandler tests that the document is served as JSON.
+func TestOpenAPIHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /openapi.json", OpenAPIHandler())
+	mux.Handle("GET /docs", DocsHandler())
+	handler := checkContract(t, mux)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	var doc struct {
+		OpenAPI string         `json:"openapi"`
+		Paths   map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Error unmarshalling the document: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") || doc.Paths["/recommend"] == nil {
+		t.Errorf("Unexpected document: openapi %q with paths %v", doc.OpenAPI, doc.Paths)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if !strings.Contains(rec.Body.String(), `spec-url="/openapi.json"`) {
+		t.Errorf("Expected the docs page to load /openapi.json, got %s", rec.Body)
+	}
+}
+
+// newContractMux registers every handler the way main does, checked against
+// the OpenAPI document.
+func newContractMux(t *testing.T, db *sql.DB) http.Handler {
+	t.Helper()
+	admin := func(h http.Handler) http.Handler { return RequireToken("secret", h) }
+	mux := http.NewServeMux()
+	mux.Handle("GET /healthz", LivenessHandler())
+	mux.Handle("GET /readyz", ReadinessHandler(db, &Readiness{}))
+	mux.Handle("GET /v1/recommend", RecommendHandler(db, nil, nil))
+	mux.Handle("GET /v2/recommend", RecommendV2Handler(db, nil, nil))
+	mux.Handle("POST /v2/recommend/batch", RecommendBatchHandler(db, nil, nil))
+	mux.Handle("/recommend", Deprecated(time.Now(), "/v1/recommend", RecommendHandler(db, nil, nil)))
+	mux.Handle("GET /restaurants/{id}", GetRestaurantHandler(db))
+	mux.Handle("POST /restaurants/{id}/feedback", FeedbackHandler(db))
+	graphQL := GraphQLHandler(db, nil, nil)
+	mux.Handle("GET /graphql", graphQL)
+	mux.Handle("POST /graphql", graphQL)
+	mux.Handle("GET /restaurants", ListRestaurantsHandler(db))
+	mux.Handle("POST /restaurants", admin(CreateRestaurantHandler(db)))
+	mux.Handle("POST /restaurants/import", admin(ImportRestaurantsHandler(db)))
+	mux.Handle("GET /restaurants/export", admin(ExportRestaurantsHandler(db)))
+	mux.Handle("PUT /restaurants/{id}", admin(UpdateRestaurantHandler(db)))
+	mux.Handle("PATCH /restaurants/{id}", admin(PatchRestaurantHandler(db)))
+	mux.Handle("DELETE /restaurants/{id}", admin(DeleteRestaurantHandler(db)))
+	return checkContract(t, mux)
+}
+
+// TestOpenAPIContract exercises every documented operation, including its
+// error responses, and checks each exchange against the OpenAPI document.
+func TestOpenAPIContract(t *testing.T) {
+	const id = "3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70"
+	restaurantRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows(restaurantRowColumns).
+			AddRow(id, "Pizza Hut", "Italian", "Wherever Street 99", "09:00", "23:00", true, true,
+				"", "", "", 80, true, true, true)
+	}
+	selectRestaurant := regexp.QuoteMeta("SELECT " + selectRestaurantColumns + " FROM restaurants WHERE publicId = @p1")
+	expectStyles := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT DISTINCT style FROM restaurants").
+			WillReturnRows(sqlmock.NewRows([]string{"style"}).AddRow("Italian"))
+	}
+	valid := `{"name":"Pizza Hut","style":"Italian","openHour":"09:00","closeHour":"23:00"}`
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		body        string
+		contentType string
+		admin       bool
+		expect      func(sqlmock.Sqlmock)
+		status      int
+	}{
+		{name: "liveness", method: http.MethodGet, target: "/healthz", status: http.StatusOK},
+		{
+			name: "readiness", method: http.MethodGet, target: "/readyz", status: http.StatusServiceUnavailable,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+				mock.ExpectQuery("SELECT TOP").WillReturnError(errors.New("connection refused"))
+				mock.ExpectQuery("SELECT TOP").WillReturnError(errors.New("connection refused"))
+			},
+		},
+		{
+			name: "recommend", method: http.MethodGet, target: "/recommend?query=Italian", status: http.StatusOK,
+			expect: func(mock sqlmock.Sqlmock) {
+				expectStyles(mock)
+				mock.ExpectQuery("SELECT .* FROM restaurants WHERE style").WillReturnRows(restaurantRow())
+			},
+		},
+		{
+			name: "recommend v1", method: http.MethodGet, target: "/v1/recommend?query=Italian", status: http.StatusOK,
+			expect: func(mock sqlmock.Sqlmock) {
+				expectStyles(mock)
+				mock.ExpectQuery("SELECT .* FROM restaurants WHERE style").WillReturnRows(restaurantRow())
+			},
+		},
+		{
+			name: "recommend v2", method: http.MethodGet, target: "/v2/recommend?query=Italian&limit=5", status: http.StatusOK,
+			expect: func(mock sqlmock.Sqlmock) {
+				expectStyles(mock)
+				mock.ExpectQuery("SELECT .* FROM restaurants WHERE style").WillReturnRows(restaurantRow())
+			},
+		},
+		{
+			name: "recommend v2 with alternatives", method: http.MethodGet, target: "/v2/recommend?query=Italian+open+at+8:45am", status: http.StatusOK,
+			expect: func(mock sqlmock.Sqlmock) {
+				expectStyles(mock)
+				mock.ExpectQuery("SELECT .* FROM restaurants WHERE style").WillReturnRows(sqlmock.NewRows(restaurantRowColumns))
+				mock.ExpectQuery("SELECT .* FROM restaurants WHERE style").WillReturnRows(restaurantRow())
+			},
+		},
+		{name: "recommend v2 bad limit", method: http.MethodGet, target: "/v2/recommend?query=Italian&limit=500", status: http.StatusBadRequest},
+		{
+			name: "recommend batch", method: http.MethodPost, target: "/v2/recommend/batch", status: http.StatusOK,
+			body: `{"queries":[{"query":"Italian","limit":1},{"query":""}]}`,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT .* FROM restaurants").WillReturnRows(restaurantRow())
+			},
+		},
+		{name: "recommend batch empty", method: http.MethodPost, target: "/v2/recommend/batch", body: `{"queries":[]}`, status: http.StatusBadRequest},
+		{name: "recommend without query", method: http.MethodGet, target: "/recommend", status: http.StatusBadRequest, expect: expectStyles},
+		{
+			name: "recommend with no match", method: http.MethodGet, target: "/recommend?query=Italian+open+at+8am", status: http.StatusNotFound,
+			expect: func(mock sqlmock.Sqlmock) {
+				expectStyles(mock)
+				mock.ExpectQuery("SELECT .* FROM restaurants WHERE style").WillReturnRows(sqlmock.NewRows(restaurantRowColumns))
+				mock.ExpectQuery("SELECT .* FROM restaurants WHERE style").WillReturnRows(restaurantRow())
+			},
+		},
+		{
+			name: "recommend with store down", method: http.MethodGet, target: "/recommend?query=Italian", status: http.StatusServiceUnavailable,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT DISTINCT style FROM restaurants").WillReturnError(errors.New("connection refused"))
+			},
+		},
+		{
+			name: "get", method: http.MethodGet, target: "/restaurants/" + id, status: http.StatusOK,
+			expect: func(mock sqlmock.Sqlmock) { mock.ExpectQuery(selectRestaurant).WillReturnRows(restaurantRow()) },
+		},
+		{
+			name: "get unknown", method: http.MethodGet, target: "/restaurants/" + id, status: http.StatusNotFound,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectRestaurant).WillReturnRows(sqlmock.NewRows(restaurantRowColumns))
+			},
+		},
+		{
+			name: "feedback", method: http.MethodPost, target: "/restaurants/" + id + "/feedback", status: http.StatusCreated,
+			body: `{"rating":4,"comment":"Great crust"}`,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("INSERT INTO restaurant_feedback").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+			},
+		},
+		{
+			name: "feedback unknown restaurant", method: http.MethodPost, target: "/restaurants/" + id + "/feedback", status: http.StatusNotFound,
+			body: `{"rating":4}`,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("INSERT INTO restaurant_feedback").WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+			},
+		},
+		{name: "feedback invalid", method: http.MethodPost, target: "/restaurants/" + id + "/feedback", body: `{"rating":9}`, status: http.StatusUnprocessableEntity},
+		{
+			name: "graphql", method: http.MethodPost, target: "/graphql", body: `{"query":"{ styles }"}`, status: http.StatusOK,
+			expect: expectStyles,
+		},
+		{name: "graphql get", method: http.MethodGet, target: "/graphql?query=%7B+nope+%7D", status: http.StatusOK},
+		{name: "graphql without query", method: http.MethodPost, target: "/graphql", body: `{}`, status: http.StatusBadRequest},
+		{name: "get malformed id", method: http.MethodGet, target: "/restaurants/7", status: http.StatusBadRequest},
+		{
+			name: "list", method: http.MethodGet, target: "/restaurants?sort=-closeHour&limit=1&vegetarian=true&openAt=12:00", status: http.StatusOK,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT TOP .* FROM restaurants").WillReturnRows(restaurantRow().
+					AddRow("5d6e7f80-9a0b-4c1d-8e2f-3a4b5c6d7e8f", "Luigi's", "Italian", "1 Pasta Lane", "12:00", "22:00", true, false,
+						"", "", "", 20, false, false, false))
+			},
+		},
+		{name: "list bad sort", method: http.MethodGet, target: "/restaurants?sort=rating", status: http.StatusBadRequest},
+		{name: "list bad cursor", method: http.MethodGet, target: "/restaurants?cursor=nope", status: http.StatusBadRequest},
+		{name: "create without token", method: http.MethodPost, target: "/restaurants", body: valid, status: http.StatusUnauthorized},
+		{
+			name: "create", method: http.MethodPost, target: "/restaurants", body: valid, admin: true, status: http.StatusCreated,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("INSERT INTO restaurants").WillReturnRows(sqlmock.NewRows([]string{"publicId"}).AddRow(id))
+			},
+		},
+		{
+			name: "create invalid", method: http.MethodPost, target: "/restaurants", admin: true, status: http.StatusUnprocessableEntity,
+			body: `{"name":"Pizza Hut","style":"Italian","openHour":"9am","closeHour":"23:00"}`,
+		},
+		{name: "create unknown field", method: http.MethodPost, target: "/restaurants", body: `{"stars":5}`, admin: true, status: http.StatusBadRequest},
+		{
+			name: "update", method: http.MethodPut, target: "/restaurants/" + id, body: valid, admin: true, status: http.StatusOK,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE restaurants").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "patch", method: http.MethodPatch, target: "/restaurants/" + id, body: `{"wifi":false}`, admin: true, status: http.StatusOK,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectRestaurant).WillReturnRows(restaurantRow())
+				mock.ExpectExec("UPDATE restaurants").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "delete", method: http.MethodDelete, target: "/restaurants/" + id, admin: true, status: http.StatusNoContent,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM restaurants").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "import dry run", method: http.MethodPost, target: "/restaurants/import?format=csv&dryRun=true", admin: true, status: http.StatusOK,
+			contentType: "text/csv",
+			body:        "name,style,address,openHour,closeHour\nPizza Hut,Italian,Wherever Street 99,09:00,23:00\n",
+		},
+		{name: "import bad dryRun", method: http.MethodPost, target: "/restaurants/import?dryRun=maybe", admin: true, status: http.StatusBadRequest, contentType: "application/x-ndjson"},
+		{
+			name: "export", method: http.MethodGet, target: "/restaurants/export?format=json&columns=name,style", admin: true, status: http.StatusOK,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT .* FROM restaurants").WillReturnRows(restaurantRow())
+			},
+		},
+		{name: "export unknown column", method: http.MethodGet, target: "/restaurants/export?columns=stars", admin: true, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock DB: %v", err)
+			}
+			defer db.Close()
+			if tt.expect != nil {
+				tt.expect(mock)
+			}
+
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, tt.target, body)
+			if tt.body != "" {
+				contentType := tt.contentType
+				if contentType == "" {
+					contentType = "application/json"
+				}
+				req.Header.Set("Content-Type", contentType)
+			} else if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.admin {
+				req.Header.Set("Authorization", "Bearer secret")
+			}
+			ctx, cancel := context.WithTimeout(req.Context(), time.Second)
+			defer cancel()
+
+			rec := httptest.NewRecorder()
+			newContractMux(t, db).ServeHTTP(rec, req.WithContext(ctx))
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/paulmach/osm"
+	"github.com/paulmach/osm/osmpbf"
+)
+
+// OpenStreetMap import formats, accepted wherever ImportRestaurants formats are.
+const (
+	ImportFormatOverpass = "overpass"
+	ImportFormatOSMPBF   = "osm-pbf"
+)
+
+// overpassResponse is the subset of an Overpass API JSON response we read.
+type overpassResponse struct {
+	Elements []struct {
+		Type string            `json:"type"`
+		ID   int64             `json:"id"`
+		Tags map[string]string `json:"tags"`
+	} `json:"elements"`
+}
+
+// decodeOverpassRows maps the amenity=restaurant nodes of an Overpass JSON file to rows.
+func decodeOverpassRows(r io.Reader) ([]importRow, error) {
+	var resp overpassResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("reading Overpass JSON: %w", err)
+	}
+
+	var rows []importRow
+	for i, e := range resp.Elements {
+		if e.Type != "node" || e.Tags["amenity"] != "restaurant" {
+			continue
+		}
+		rows = append(rows, osmRow(i+1, fmt.Sprintf("node/%d", e.ID), e.Tags))
+	}
+	return rows, nil
+}
+
+// decodeOSMPBFRows maps the amenity=restaurant nodes of an OSM PBF extract to
+// rows. Decoding stops with ctx's error when ctx is cancelled.
+func decodeOSMPBFRows(ctx context.Context, r io.Reader) ([]importRow, error) {
+	scanner := osmpbf.New(ctx, r, runtime.GOMAXPROCS(0))
+	defer scanner.Close()
+	scanner.SkipWays = true
+	scanner.SkipRelations = true
+	scanner.FilterNode = func(n *osm.Node) bool {
+		return n.Tags.Find("amenity") == "restaurant"
+	}
+
+	var rows []importRow
+	for scanner.Scan() {
+		node, ok := scanner.Object().(*osm.Node)
+		if !ok {
+			continue
+		}
+		rows = append(rows, osmRow(len(rows)+1, fmt.Sprintf("node/%d", node.ID), node.Tags.Map()))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading OSM PBF: %w", err)
+	}
+	// The scanner reports cancellation only while there is input left to read.
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("reading OSM PBF: %w", err)
+	}
+	return rows, nil
+}
+
+// osmRow builds an import row from the tags of an OSM element.
+func osmRow(line int, source string, tags map[string]string) importRow {
+	row := importRow{line: line, source: source, restaurant: osmRestaurant(tags)}
+	if hours := tags["opening_hours"]; hours != "" {
+		open, close, err := translateOpeningHours(hours)
+		if err != nil {
+			row.err = err
+		}
+		row.restaurant.OpenHour, row.restaurant.CloseHour = open, close
+	}
+	return row
+}
+
+// osmRestaurant maps OSM tags onto the Restaurant fields. Hours are handled by translateOpeningHours.
+func osmRestaurant(tags map[string]string) Restaurant {
+	return Restaurant{
+		Name:                 tags["name"],
+		Style:                osmCuisine(tags["cuisine"]),
+		Address:              osmAddress(tags),
+		Vegetarian:           tags["diet:vegetarian"] == "yes" || tags["diet:vegetarian"] == "only",
+		Deliveries:           tags["delivery"] == "yes",
+		Phone:                firstTag(tags, "phone", "contact:phone"),
+		Website:              firstTag(tags, "website", "contact:website"),
+		Email:                firstTag(tags, "email", "contact:email"),
+		SeatingCapacity:      osmCapacity(tags["capacity"]),
+		WiFi:                 tags["internet_access"] == "wlan" || tags["internet_access"] == "yes",
+		WheelchairAccessible: tags["wheelchair"] == "yes",
+	}
+}
+
+// firstTag returns the value of the first of keys that is set.
+func firstTag(tags map[string]string, keys ...string) string {
+	for _, k := range keys {
+		if v := tags[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// osmCuisine turns the first value of a cuisine tag such as "italian;pizza" into a style like "Italian".
+func osmCuisine(cuisine string) string {
+	first, _, _ := strings.Cut(cuisine, ";")
+	words := strings.Fields(strings.ReplaceAll(strings.TrimSpace(first), "_", " "))
+	for i, w := range words {
+		runes := []rune(w)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+// osmAddress formats the addr:* tags as "123 Main Street, City".
+func osmAddress(tags map[string]string) string {
+	street := strings.TrimSpace(tags["addr:housenumber"] + " " + tags["addr:street"])
+	if city := tags["addr:city"]; city != "" {
+		if street == "" {
+			return city
+		}
+		return street + ", " + city
+	}
+	return street
+}
+
+// osmCapacity parses a capacity tag, ignoring values that are not plain numbers.
+func osmCapacity(capacity string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(capacity))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// osmTimeSpan matches a time span such as "11:30-14:00" or "18:00-02:00".
+var osmTimeSpan = regexp.MustCompile(`(\d{1,2}):(\d{2})\s*-\s*(\d{1,2}):(\d{2})`)
+
+// translateOpeningHours converts an OSM opening_hours value into our single
+// daily opening window. Restaurants only have one open and close hour, so the
+// window runs from the earliest opening to the latest closing across all rules,
+// ignoring day selectors; spans past midnight such as "18:00-02:00" keep their
+// overnight closing hour.
+func translateOpeningHours(value string) (string, string, error) {
+	value = strings.TrimSpace(value)
+	if value == "24/7" {
+		return "00:00", "23:59", nil
+	}
+
+	spans := osmTimeSpan.FindAllStringSubmatch(value, -1)
+	if len(spans) == 0 {
+		return "", "", fmt.Errorf("unsupported opening_hours %q", value)
+	}
+
+	earliest, latest := 48*60, -1
+	for _, span := range spans {
+		start, err := osmMinutes(span[1], span[2])
+		if err != nil {
+			return "", "", fmt.Errorf("invalid opening_hours %q: %w", value, err)
+		}
+		end, err := osmMinutes(span[3], span[4])
+		if err != nil {
+			return "", "", fmt.Errorf("invalid opening_hours %q: %w", value, err)
+		}
+		if end <= start {
+			end += 24 * 60
+		}
+		earliest = min(earliest, start)
+		latest = max(latest, end)
+	}
+
+	if latest-earliest >= 24*60 {
+		return "00:00", "23:59", nil
+	}
+	return formatMinutes(earliest), formatMinutes(latest % (24 * 60)), nil
+}
+
+// osmMinutes converts an OSM hour and minute, where hours may run up to 48, into minutes.
+func osmMinutes(hour, minute string) (int, error) {
+	h, _ := strconv.Atoi(hour)
+	m, _ := strconv.Atoi(minute)
+	if h > 48 || m > 59 {
+		return 0, errors.New("time out of range " + hour + ":" + minute)
+	}
+	return h*60 + m, nil
+}
+
+// formatMinutes formats minutes since midnight as "HH:MM".
+func formatMinutes(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
@@ -0,0 +1,100 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestTranslateOpeningHours checks the translation of OSM opening_hours into one daily window.
+func TestTranslateOpeningHours(t *testing.T) {
+	tests := []struct {
+		value       string
+		open, close string
+	}{
+		{"Mo-Su 11:00-22:00", "11:00", "22:00"},
+		{"Mo-Fr 11:30-14:00,18:00-22:30; Sa 12:00-23:00; Su off", "11:30", "23:00"},
+		{"Th-Sa 18:00-02:00", "18:00", "02:00"},
+		{"Mo-Fr 09:00-24:00", "09:00", "00:00"},
+		{"Fr-Sa 20:00-26:00; Mo-Th 9:00-17:00", "09:00", "02:00"},
+		{"24/7", "00:00", "23:59"},
+		{"Mo-Su 06:00-06:00", "00:00", "23:59"},
+	}
+	for _, tt := range tests {
+		open, close, err := translateOpeningHours(tt.value)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.value, err)
+			continue
+		}
+		if open != tt.open || close != tt.close {
+			t.Errorf("%q: expected %s-%s, got %s-%s", tt.value, tt.open, tt.close, open, close)
+		}
+	}
+
+	for _, value := range []string{"sunrise-sunset", "Mo-Fr 10:00-99:00"} {
+		if _, _, err := translateOpeningHours(value); err == nil {
+			t.Errorf("%q: expected an error", value)
+		}
+	}
+}
+
+// TestDecodeOverpassRows checks that restaurant nodes are mapped onto restaurants.
+func TestDecodeOverpassRows(t *testing.T) {
+	input := `{"elements":[
+	  {"type":"node","id":101,"lat":52.5,"lon":13.4,"tags":{
+	    "amenity":"restaurant","name":"Trattoria Roma","cuisine":"italian;pizza",
+	    "opening_hours":"Mo-Sa 12:00-23:00","diet:vegetarian":"yes","delivery":"no",
+	    "addr:housenumber":"12","addr:street":"Main Street","addr:city":"Springfield",
+	    "contact:phone":"+1 555 0123","wheelchair":"yes","internet_access":"wlan","capacity":"60"}},
+	  {"type":"node","id":102,"tags":{"amenity":"cafe","name":"Not A Restaurant"}},
+	  {"type":"way","id":103,"tags":{"amenity":"restaurant","name":"A Building"}},
+	  {"type":"node","id":104,"tags":{"amenity":"restaurant","name":"Odd Hours","cuisine":"fast_food","opening_hours":"sunrise-sunset"}}
+	]}`
+
+	rows, err := decodeOverpassRows(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("decodeOverpassRows returned error: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 restaurant nodes, got %d", len(rows))
+	}
+
+	got := rows[0]
+	if got.err != nil || got.source != "node/101" {
+		t.Errorf("unexpected first row: %+v", got)
+	}
+	want := Restaurant{
+		Name:                 "Trattoria Roma",
+		Style:                "Italian",
+		Address:              "12 Main Street, Springfield",
+		OpenHour:             "12:00",
+		CloseHour:            "23:00",
+		Vegetarian:           true,
+		Phone:                "+1 555 0123",
+		SeatingCapacity:      60,
+		WiFi:                 true,
+		WheelchairAccessible: true,
+	}
+	if got.restaurant != want {
+		t.Errorf("unexpected restaurant:\n got %+v\nwant %+v", got.restaurant, want)
+	}
+	if err := validateRestaurant(got.restaurant); err != nil {
+		t.Errorf("expected mapped restaurant to be valid, got %v", err)
+	}
+
+	if rows[1].restaurant.Style != "Fast Food" || rows[1].err == nil {
+		t.Errorf("expected unsupported hours error for node/104, got %+v", rows[1])
+	}
+}
+
+// TestDecodeOSMPBFRows_Cancelled tests that decoding stops when the caller's
+// context is cancelled.
+func TestDecodeOSMPBFRows_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rows, err := decodeOSMPBFRows(ctx, strings.NewReader(""))
+	if !errors.Is(err, context.Canceled) || rows != nil {
+		t.Errorf("expected context.Canceled and no rows, got %v and %d rows", err, len(rows))
+	}
+}
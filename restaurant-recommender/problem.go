@@ -0,0 +1,121 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// ProblemContentType is the media type of error responses (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// ErrorCode is a stable, machine-readable identifier for an error response.
+type ErrorCode string
+
+// Error codes returned in problem responses.
+const (
+	CodeQueryRequired      ErrorCode = "QUERY_REQUIRED"
+	CodeNoMatch            ErrorCode = "NO_MATCH"
+	CodeStoreUnavailable   ErrorCode = "STORE_UNAVAILABLE"
+	CodeStoreTimeout       ErrorCode = "STORE_TIMEOUT"
+	CodeRestaurantNotFound ErrorCode = "RESTAURANT_NOT_FOUND"
+	CodeInvalidRequest     ErrorCode = "INVALID_REQUEST"
+	CodeValidationFailed   ErrorCode = "VALIDATION_FAILED"
+	CodeUnauthorized       ErrorCode = "UNAUTHORIZED"
+	CodeInternal           ErrorCode = "INTERNAL"
+	CodeClientClosed       ErrorCode = "CLIENT_CLOSED_REQUEST"
+)
+
+// statusClientClosedRequest is the non-standard status, borrowed from nginx,
+// recorded for requests whose client disconnected before they were answered,
+// so they are not counted as server errors.
+const statusClientClosedRequest = 499
+
+// errorKinds gives the status and title of each error code.
+var errorKinds = map[ErrorCode]struct {
+	status int
+	title  string
+}{
+	CodeQueryRequired:      {http.StatusBadRequest, "Query parameter is required"},
+	CodeNoMatch:            {http.StatusNotFound, "No restaurant found matching the criteria"},
+	CodeStoreUnavailable:   {http.StatusServiceUnavailable, "Restaurant store is unavailable"},
+	CodeStoreTimeout:       {http.StatusGatewayTimeout, "Timed out accessing restaurants"},
+	CodeRestaurantNotFound: {http.StatusNotFound, "Restaurant not found"},
+	CodeInvalidRequest:     {http.StatusBadRequest, "Invalid request"},
+	CodeValidationFailed:   {http.StatusUnprocessableEntity, "Request failed validation"},
+	CodeUnauthorized:       {http.StatusUnauthorized, "A valid bearer token is required"},
+	CodeInternal:           {http.StatusInternalServerError, "Internal server error"},
+	CodeClientClosed:       {statusClientClosedRequest, "Client closed the request"},
+}
+
+// Problem is an RFC 7807 problem details body, extended with the error code,
+// the request ID and, for NO_MATCH, the criteria parsed from the query and
+// any alternatives found by relaxing them.
+type Problem struct {
+	Type         string         `json:"type"`
+	Title        string         `json:"title"`
+	Status       int            `json:"status"`
+	Detail       string         `json:"detail,omitempty"`
+	Instance     string         `json:"instance,omitempty"`
+	Code         ErrorCode      `json:"code"`
+	RequestID    string         `json:"requestId,omitempty"`
+	Criteria     *QueryCriteria `json:"criteria,omitempty"`
+	Alternatives []Alternative  `json:"alternatives,omitempty"`
+}
+
+// newProblem describes an error with the given code for request r.
+func newProblem(r *http.Request, code ErrorCode, detail string) Problem {
+	kind := errorKinds[code]
+	return Problem{
+		// Relative type URIs name the error without promising a documentation page.
+		Type:      "/problems/" + strings.ToLower(strings.ReplaceAll(string(code), "_", "-")),
+		Title:     kind.title,
+		Status:    kind.status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      code,
+		RequestID: RequestIDFromContext(r.Context()),
+	}
+}
+
+// writeProblem writes p as a problem+json response.
+func writeProblem(w http.ResponseWriter, r *http.Request, p Problem) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		slog.ErrorContext(r.Context(), "Error encoding response", "err", err)
+	}
+}
+
+// writeError writes a problem response with the given code and detail.
+func writeError(w http.ResponseWriter, r *http.Request, code ErrorCode, detail string) {
+	writeProblem(w, r, newProblem(r, code, detail))
+}
+
+// storeErrorCode maps a failed data-layer call to an error code:
+// CLIENT_CLOSED_REQUEST when the call was cancelled because the client went
+// away, STORE_TIMEOUT when it ran out of time and STORE_UNAVAILABLE otherwise.
+func storeErrorCode(err error) ErrorCode {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return CodeClientClosed
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeStoreTimeout
+	}
+	return CodeStoreUnavailable
+}
+
+// logStoreError logs a failed data-layer call with msg and args. A call
+// cancelled because the client went away is not a failure of the service, so
+// it is logged at debug level rather than as an error.
+func logStoreError(ctx context.Context, msg string, err error, args ...any) {
+	args = append(args, "err", err)
+	if errors.Is(err, context.Canceled) {
+		slog.DebugContext(ctx, msg, args...)
+		return
+	}
+	slog.ErrorContext(ctx, msg, args...)
+}
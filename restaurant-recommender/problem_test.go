@@ -0,0 +1,93 @@
+package restaurantrecommender
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestWriteError tests the problem+json body written for an error code.
+func TestWriteError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/restaurants/7", nil)
+	req = req.WithContext(WithRequestID(req.Context(), "req-1"))
+	rec := httptest.NewRecorder()
+
+	writeError(rec, req, CodeInvalidRequest, `invalid restaurant id "7"`)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Errorf("expected Content-Type %q, got %q", ProblemContentType, ct)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("error unmarshalling %q: %v", rec.Body.String(), err)
+	}
+	want := map[string]any{
+		"type":      "/problems/invalid-request",
+		"title":     "Invalid request",
+		"status":    float64(http.StatusBadRequest),
+		"detail":    `invalid restaurant id "7"`,
+		"instance":  "/restaurants/7",
+		"code":      "INVALID_REQUEST",
+		"requestId": "req-1",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestErrorKinds tests that every error code has a status and title.
+func TestErrorKinds(t *testing.T) {
+	for code, kind := range errorKinds {
+		if kind.status < 400 || kind.title == "" {
+			t.Errorf("%s: incomplete error kind %+v", code, kind)
+		}
+	}
+}
+
+// TestStoreErrorCode tests the mapping of data-layer errors to error codes.
+func TestStoreErrorCode(t *testing.T) {
+	if code := storeErrorCode(fmt.Errorf("query: %w", context.DeadlineExceeded)); code != CodeStoreTimeout {
+		t.Errorf("expected %s for a timeout, got %s", CodeStoreTimeout, code)
+	}
+	if code := storeErrorCode(errors.New("connection refused")); code != CodeStoreUnavailable {
+		t.Errorf("expected %s, got %s", CodeStoreUnavailable, code)
+	}
+	if code := storeErrorCode(fmt.Errorf("query: %w", context.Canceled)); code != CodeClientClosed {
+		t.Errorf("expected %s for a disconnected client, got %s", CodeClientClosed, code)
+	}
+}
+
+// TestWriteStoreError_ClientGone tests that a request whose client went away
+// is neither answered as a server error nor logged as one.
+func TestWriteStoreError_ClientGone(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	defer slog.SetDefault(previous)
+
+	req := httptest.NewRequest(http.MethodGet, "/restaurants/7", nil)
+	rec := httptest.NewRecorder()
+	writeStoreError(rec, req, fmt.Errorf("query: %w", context.Canceled))
+	if rec.Code != statusClientClosedRequest {
+		t.Errorf("expected status %d, got %d", statusClientClosedRequest, rec.Code)
+	}
+	if !strings.Contains(buf.String(), `"level":"DEBUG"`) || strings.Contains(buf.String(), `"level":"ERROR"`) {
+		t.Errorf("expected a debug log line, got %s", buf.String())
+	}
+
+	buf.Reset()
+	writeStoreError(httptest.NewRecorder(), req, errors.New("connection refused"))
+	if !strings.Contains(buf.String(), `"level":"ERROR"`) {
+		t.Errorf("expected an error log line, got %s", buf.String())
+	}
+}
@@ -0,0 +1,124 @@
+package restaurantrecommender
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// openAtClause is true when a restaurant is open at the time bound to @%[1]s.
+// Hours are zero-padded "HH:MM" strings, so they compare correctly as text. It
+// mirrors isOpen: opening and closing minutes are exclusive, and a closing hour
+// before the opening hour means the restaurant closes after midnight.
+const openAtClause = `((openHour < closeHour AND openHour < @%[1]s AND @%[1]s < closeHour)` +
+	` OR (closeHour < openHour AND (openHour < @%[1]s OR @%[1]s < closeHour)))`
+
+// restaurantQuery accumulates the WHERE conditions and parameters of a restaurant query.
+type restaurantQuery struct {
+	conditions []string
+	args       []any
+}
+
+// param binds a value to the next @pN parameter and returns its name.
+func (q *restaurantQuery) param(value any) string {
+	name := fmt.Sprintf("p%d", len(q.args)+1)
+	q.args = append(q.args, sql.Named(name, value))
+	return name
+}
+
+// where adds a condition comparing a column to a bound value.
+func (q *restaurantQuery) where(column string, value any) {
+	q.conditions = append(q.conditions, fmt.Sprintf("%s = @%s", column, q.param(value)))
+}
+
+// buildRestaurantQuery translates query criteria into a parameterised SELECT
+// returning the restaurants that restaurantMatchesCriteria would accept at now.
+func buildRestaurantQuery(criteria QueryCriteria, now time.Time) (string, []any) {
+	var q restaurantQuery
+	q.filter(criteria, now)
+	return "SELECT " + selectRestaurantColumns + " FROM restaurants" + q.whereClause() + " ORDER BY id", q.args
+}
+
+// filter adds the conditions selecting the restaurants that
+// restaurantMatchesCriteria would accept at now.
+func (q *restaurantQuery) filter(criteria QueryCriteria, now time.Time) {
+	// Style comparisons rely on the database's case-insensitive default collation.
+	if criteria.Style != "" {
+		q.where("style", criteria.Style)
+	}
+	if criteria.Vegetarian != nil {
+		q.where("vegetarian", *criteria.Vegetarian)
+	}
+	if criteria.Delivers != nil {
+		q.where("deliveries", *criteria.Delivers)
+	}
+	if criteria.Parking != nil {
+		q.where("parking", *criteria.Parking)
+	}
+	if criteria.WiFi != nil {
+		q.where("wifi", *criteria.WiFi)
+	}
+	if criteria.Accessible != nil {
+		q.where("wheelchairAccessible", *criteria.Accessible)
+	}
+
+	var checkTime time.Time
+	if criteria.OpenAt != nil {
+		checkTime = *criteria.OpenAt
+	} else if criteria.OpenNow {
+		checkTime = now
+	}
+	if !checkTime.IsZero() {
+		q.conditions = append(q.conditions, fmt.Sprintf(openAtClause, q.param(checkTime.Format("15:04"))))
+	}
+}
+
+// whereClause joins the conditions into a WHERE clause, or returns "" if there are none.
+func (q *restaurantQuery) whereClause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+// restaurantSortColumns maps the sort keys of a restaurant listing to their
+// columns. Hours are zero-padded "HH:MM" strings, so closing times sort as text.
+var restaurantSortColumns = map[string]string{
+	"name":      "name",
+	"style":     "style",
+	"closeHour": "closeHour",
+}
+
+// listOptions selects a page of a restaurant listing.
+type listOptions struct {
+	criteria QueryCriteria
+	sort     string // a key of restaurantSortColumns
+	desc     bool
+	after    *restaurantCursor // the last restaurant of the previous page
+	limit    int
+}
+
+// buildListQuery translates listing options into a parameterised SELECT of up
+// to limit restaurants. Pages are read by keyset rather than OFFSET: rows
+// sort by the chosen column with the public ID breaking ties, and a page
+// starts after the previous page's last row, so the database seeks straight
+// to it however deep the page is.
+func buildListQuery(opts listOptions, now time.Time) (string, []any) {
+	var q restaurantQuery
+	q.filter(opts.criteria, now)
+
+	column := restaurantSortColumns[opts.sort]
+	cmp, dir := ">", "ASC"
+	if opts.desc {
+		cmp, dir = "<", "DESC"
+	}
+	if opts.after != nil {
+		value, id := q.param(opts.after.Value), q.param(opts.after.ID)
+		q.conditions = append(q.conditions,
+			fmt.Sprintf("(%[1]s %[2]s @%[3]s OR (%[1]s = @%[3]s AND publicId %[2]s @%[4]s))", column, cmp, value, id))
+	}
+	top := q.param(opts.limit)
+	return fmt.Sprintf("SELECT TOP (@%s) %s FROM restaurants%s ORDER BY %s %s, publicId %s",
+		top, selectRestaurantColumns, q.whereClause(), column, dir, dir), q.args
+}
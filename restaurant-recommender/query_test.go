@@ -0,0 +1,212 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestBuildRestaurantQuery checks the generated SQL and parameters.
+func TestBuildRestaurantQuery(t *testing.T) {
+	now := time.Date(2025, 3, 2, 12, 5, 0, 0, time.UTC)
+
+	query, args := buildRestaurantQuery(QueryCriteria{}, now)
+	if query != "SELECT "+selectRestaurantColumns+" FROM restaurants ORDER BY id" || len(args) != 0 {
+		t.Errorf("unexpected unfiltered query %q with args %v", query, args)
+	}
+
+	query, args = buildRestaurantQuery(QueryCriteria{
+		Style:      "Italian",
+		Vegetarian: boolPtr(true),
+		WiFi:       boolPtr(true),
+		OpenNow:    true,
+	}, now)
+	want := "SELECT " + selectRestaurantColumns + " FROM restaurants" +
+		" WHERE style = @p1 AND vegetarian = @p2 AND wifi = @p3 AND " + fmt.Sprintf(openAtClause, "p4") +
+		" ORDER BY id"
+	if query != want {
+		t.Errorf("unexpected query:\n got %s\nwant %s", query, want)
+	}
+	wantArgs := []any{sql.Named("p1", "Italian"), sql.Named("p2", true), sql.Named("p3", true), sql.Named("p4", "12:05")}
+	if fmt.Sprint(args) != fmt.Sprint(wantArgs) {
+		t.Errorf("unexpected args %v, want %v", args, wantArgs)
+	}
+}
+
+// sqlMatches evaluates a query from buildRestaurantQuery against a restaurant,
+// interpreting the small subset of SQL the builder generates.
+func sqlMatches(t *testing.T, r Restaurant, query string, args []any) bool {
+	t.Helper()
+	params := make(map[string]any)
+	for _, a := range args {
+		named := a.(sql.NamedArg)
+		params[named.Name] = named.Value
+	}
+
+	_, where, ok := strings.Cut(strings.TrimSuffix(query, " ORDER BY id"), " WHERE ")
+	if !ok {
+		return true
+	}
+	equals := regexp.MustCompile(`^(\w+) = @(p\d+)$`)
+	openAt := regexp.MustCompile(`@(p\d+)`)
+	for _, cond := range splitConditions(where) {
+		if m := equals.FindStringSubmatch(cond); m != nil {
+			var value any
+			for _, f := range exportFields {
+				if f.name == m[1] {
+					value = f.value(r)
+				}
+			}
+			if s, ok := value.(string); ok {
+				// Mirror the database's case-insensitive collation.
+				if !strings.EqualFold(s, params[m[2]].(string)) {
+					return false
+				}
+			} else if value != params[m[2]] {
+				return false
+			}
+			continue
+		}
+		name := openAt.FindStringSubmatch(cond)[1]
+		if cond != fmt.Sprintf(openAtClause, name) {
+			t.Fatalf("unrecognised condition %q", cond)
+		}
+		at := params[name].(string)
+		open, close := r.OpenHour, r.CloseHour
+		if !((open < close && open < at && at < close) || (close < open && (open < at || at < close))) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitConditions splits a WHERE clause on its top-level ANDs.
+func splitConditions(where string) []string {
+	var conds []string
+	depth, start := 0, 0
+	for i := 0; i < len(where); i++ {
+		switch where[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth == 0 && strings.HasPrefix(where[i:], " AND ") {
+			conds = append(conds, where[start:i])
+			start = i + len(" AND ")
+		}
+	}
+	return append(conds, where[start:])
+}
+
+// TestBuildRestaurantQuery_MatchesReference checks that the SQL filter selects
+// exactly the restaurants accepted by restaurantMatchesCriteria.
+func TestBuildRestaurantQuery_MatchesReference(t *testing.T) {
+	restaurants := []Restaurant{
+		{Name: "Day", Style: "Italian", OpenHour: "09:00", CloseHour: "23:00", Vegetarian: true, Deliveries: true, Parking: true},
+		{Name: "Late", Style: "Mexican", OpenHour: "18:00", CloseHour: "02:00", Deliveries: true, WiFi: true},
+		{Name: "Lunch", Style: "korean", OpenHour: "11:00", CloseHour: "14:30", WheelchairAccessible: true},
+		{Name: "Midnight", Style: "Italian", OpenHour: "12:00", CloseHour: "00:00", Vegetarian: true, WiFi: true},
+		{Name: "Closed", Style: "Thai", OpenHour: "10:00", CloseHour: "10:00"},
+	}
+	at := func(hour, minute int) *time.Time {
+		t := time.Date(2025, 3, 2, hour, minute, 0, 0, time.UTC)
+		return &t
+	}
+	criteria := []QueryCriteria{
+		{},
+		{Style: "Italian"},
+		{Style: "Korean"},
+		{Vegetarian: boolPtr(true)},
+		{Vegetarian: boolPtr(false), Delivers: boolPtr(true)},
+		{Parking: boolPtr(true)},
+		{WiFi: boolPtr(true), Accessible: boolPtr(false)},
+		{OpenNow: true},
+		{OpenAt: at(1, 0)},
+		{OpenAt: at(9, 0)},
+		{OpenAt: at(10, 0)},
+		{OpenAt: at(14, 0), Style: "korean"},
+		{OpenAt: at(23, 30)},
+		{OpenAt: at(0, 0)},
+		{OpenAt: at(18, 0)},
+		{OpenAt: at(18, 1), Delivers: boolPtr(true)},
+	}
+
+	for _, now := range []time.Time{*at(3, 0), *at(12, 0), *at(22, 45)} {
+		for _, c := range criteria {
+			query, args := buildRestaurantQuery(c, now)
+			for _, r := range restaurants {
+				want := restaurantMatchesCriteria(r, c, now)
+				if got := sqlMatches(t, r, query, args); got != want {
+					t.Errorf("now %s, criteria %+v, restaurant %s: SQL match %v, reference %v",
+						now.Format("15:04"), c, r.Name, got, want)
+				}
+			}
+		}
+	}
+}
+
+// TestFindRestaurants tests that the built query is executed with its parameters.
+func TestFindRestaurants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	criteria := QueryCriteria{Style: "Mexican", Delivers: boolPtr(true)}
+	query, _ := buildRestaurantQuery(criteria, time.Now())
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs("Mexican", true).
+		WillReturnRows(sqlmock.NewRows(restaurantRowColumns).
+			AddRow("8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d", "Taco Bell", "Mexican", "123 Burrito Blvd, Somecity", "10:00", "22:00", false, true,
+				"", "", "", 40, true, false, true))
+
+	restaurants, err := findRestaurants(context.Background(), db, criteria, time.Now())
+	if err != nil {
+		t.Fatalf("findRestaurants returned error: %v", err)
+	}
+	if len(restaurants) != 1 || restaurants[0].Name != "Taco Bell" {
+		t.Errorf("unexpected restaurants: %+v", restaurants)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// TestBuildListQuery checks the keyset conditions and ordering of listing queries.
+func TestBuildListQuery(t *testing.T) {
+	now := time.Date(2025, 3, 2, 12, 5, 0, 0, time.UTC)
+
+	query, args := buildListQuery(listOptions{sort: "name", limit: 21}, now)
+	want := "SELECT TOP (@p1) " + selectRestaurantColumns + " FROM restaurants ORDER BY name ASC, publicId ASC"
+	if query != want || fmt.Sprint(args) != fmt.Sprint([]any{sql.Named("p1", 21)}) {
+		t.Errorf("unexpected first page query %q with args %v", query, args)
+	}
+
+	query, args = buildListQuery(listOptions{
+		criteria: QueryCriteria{Style: "Italian"},
+		sort:     "closeHour",
+		desc:     true,
+		after:    &restaurantCursor{Sort: "closeHour", Desc: true, Value: "23:00", ID: "3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70"},
+		limit:    6,
+	}, now)
+	want = "SELECT TOP (@p4) " + selectRestaurantColumns + " FROM restaurants" +
+		" WHERE style = @p1 AND (closeHour < @p2 OR (closeHour = @p2 AND publicId < @p3))" +
+		" ORDER BY closeHour DESC, publicId DESC"
+	if query != want {
+		t.Errorf("unexpected query:\n got %s\nwant %s", query, want)
+	}
+	wantArgs := []any{sql.Named("p1", "Italian"), sql.Named("p2", "23:00"),
+		sql.Named("p3", "3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70"), sql.Named("p4", 6)}
+	if fmt.Sprint(args) != fmt.Sprint(wantArgs) {
+		t.Errorf("unexpected args %v, want %v", args, wantArgs)
+	}
+}
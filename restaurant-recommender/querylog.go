@@ -0,0 +1,277 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxQueryLogBatch keeps a batched insert under SQL Server's 2100 parameter limit.
+const maxQueryLogBatch = 500
+
+// QueryLogConfig configures a QueryLogWriter.
+type QueryLogConfig struct {
+	// QueueSize is the number of entries buffered before the drop policy applies.
+	QueueSize int
+	// BatchSize is the maximum number of entries written per INSERT.
+	BatchSize int
+	// FlushInterval is the longest an entry waits before its batch is written.
+	FlushInterval time.Duration
+	// EnqueueTimeout is how long Log waits for space in a full queue before
+	// dropping the entry. Zero drops immediately, so requests never block.
+	EnqueueTimeout time.Duration
+}
+
+// DefaultQueryLogConfig returns the configuration used when none is given.
+func DefaultQueryLogConfig() QueryLogConfig {
+	return QueryLogConfig{QueueSize: 1000, BatchSize: 100, FlushInterval: time.Second}
+}
+
+// QueryLogStats is a snapshot of the query-log writer counters. Pending
+// entries are still queued; InFlight entries have been taken off the queue
+// into the current batch and are not yet written.
+type QueryLogStats struct {
+	Enqueued uint64 `json:"enqueued"`
+	Written  uint64 `json:"written"`
+	Dropped  uint64 `json:"dropped"`
+	Failed   uint64 `json:"failed"`
+	Pending  int    `json:"pending"`
+	InFlight int    `json:"inFlight"`
+}
+
+// queryLogEntry is a single query_logs row waiting to be written.
+type queryLogEntry struct {
+	query     string
+	response  string
+	createdAt time.Time
+	requestID string
+	// span is the request span that logged the entry, linked from the batch's span.
+	span trace.SpanContext
+}
+
+// QueryLogWriter writes query_logs rows in the background through a bounded
+// queue, batching inserts and dropping entries rather than letting logging
+// slow down or pile up behind requests. Close flushes what is queued.
+type QueryLogWriter struct {
+	db     *sql.DB
+	config QueryLogConfig
+
+	// mu guards closed; Log holds it for reading so Close can safely close entries.
+	mu      sync.RWMutex
+	closed  bool
+	entries chan queryLogEntry
+	done    chan struct{}
+
+	enqueued atomic.Uint64
+	written  atomic.Uint64
+	dropped  atomic.Uint64
+	failed   atomic.Uint64
+	inFlight atomic.Int64
+}
+
+// NewQueryLogWriter starts a writer that inserts into the query_logs table of db.
+func NewQueryLogWriter(db *sql.DB, config QueryLogConfig) *QueryLogWriter {
+	defaults := DefaultQueryLogConfig()
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaults.QueueSize
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	config.BatchSize = min(config.BatchSize, maxQueryLogBatch)
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaults.FlushInterval
+	}
+
+	w := &QueryLogWriter{
+		db:      db,
+		config:  config,
+		entries: make(chan queryLogEntry, config.QueueSize),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Log queues the query and its response for writing. It returns false if the
+// entry was dropped because the queue stayed full or the writer is closed.
+// The outcome is recorded on ctx's span, which the span of the batch that
+// writes the entry links to. Logging on a nil writer is a no-op.
+func (w *QueryLogWriter) Log(ctx context.Context, query string, response Recommendation) bool {
+	if w == nil {
+		return false
+	}
+	span := trace.SpanFromContext(ctx)
+	if !w.enqueue(ctx, query, response) {
+		span.AddEvent("query log dropped")
+		return false
+	}
+	span.AddEvent("query log queued")
+	return true
+}
+
+// enqueue adds an entry for the request in ctx to the queue, applying the
+// drop policy.
+func (w *QueryLogWriter) enqueue(ctx context.Context, query string, response Recommendation) bool {
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error marshalling response", "err", err)
+		w.dropped.Add(1)
+		return false
+	}
+	entry := queryLogEntry{
+		query:     query,
+		response:  string(responseJSON),
+		createdAt: time.Now(),
+		requestID: RequestIDFromContext(ctx),
+		span:      trace.SpanContextFromContext(ctx),
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		w.dropped.Add(1)
+		return false
+	}
+
+	select {
+	case w.entries <- entry:
+		w.enqueued.Add(1)
+		return true
+	default:
+	}
+	if w.config.EnqueueTimeout > 0 {
+		timer := time.NewTimer(w.config.EnqueueTimeout)
+		defer timer.Stop()
+		select {
+		case w.entries <- entry:
+			w.enqueued.Add(1)
+			return true
+		case <-timer.C:
+		}
+	}
+	w.dropped.Add(1)
+	return false
+}
+
+// Close stops accepting entries and waits until the queued ones are written or ctx ends.
+func (w *QueryLogWriter) Close(ctx context.Context) error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.entries)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("flushing query logs: %w", ctx.Err())
+	}
+}
+
+// Stats returns the current writer counters.
+func (w *QueryLogWriter) Stats() QueryLogStats {
+	return QueryLogStats{
+		Enqueued: w.enqueued.Load(),
+		Written:  w.written.Load(),
+		Dropped:  w.dropped.Load(),
+		Failed:   w.failed.Load(),
+		Pending:  len(w.entries),
+		InFlight: int(w.inFlight.Load()),
+	}
+}
+
+// run collects queued entries into batches and writes them until the queue is closed.
+func (w *QueryLogWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]queryLogEntry, 0, w.config.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		// The batch outlives the requests it logs, so its span starts a new
+		// trace linked to theirs rather than joining one of them.
+		links := make([]trace.Link, 0, len(batch))
+		for _, e := range batch {
+			if e.span.IsValid() {
+				links = append(links, trace.Link{SpanContext: e.span})
+			}
+		}
+		ctx, span := tracer.Start(context.Background(), "flushQueryLogs",
+			trace.WithNewRoot(),
+			trace.WithLinks(links...),
+			trace.WithAttributes(attribute.Int("query_log.batch_size", len(batch))),
+		)
+		err := insertQueryLogs(ctx, w.db, batch)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error logging queries and responses", "count", len(batch), "err", err)
+			w.failed.Add(uint64(len(batch)))
+		} else {
+			w.written.Add(uint64(len(batch)))
+		}
+		endSpan(span, err)
+		batch = batch[:0]
+		w.inFlight.Store(0)
+	}
+
+	for {
+		select {
+		case entry, ok := <-w.entries:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, entry)
+			w.inFlight.Store(int64(len(batch)))
+			if len(batch) == w.config.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// insertQueryLogs inserts a batch of entries into the query_logs table with one statement.
+func insertQueryLogs(ctx context.Context, db *sql.DB, entries []queryLogEntry) (err error) {
+	ctx, finish := withQueryTimeout(ctx, "insertQueryLogs")
+	defer finish(&err)
+
+	values := make([]string, len(entries))
+	args := make([]any, 0, 4*len(entries))
+	for i, e := range entries {
+		n := 4 * i
+		values[i] = fmt.Sprintf("(@p%d, @p%d, @p%d, @p%d)", n+1, n+2, n+3, n+4)
+		// Entries logged outside a request have no ID and store NULL.
+		requestID := sql.NullString{String: e.requestID, Valid: e.requestID != ""}
+		args = append(args,
+			sql.Named(fmt.Sprintf("p%d", n+1), e.query),
+			sql.Named(fmt.Sprintf("p%d", n+2), e.response),
+			sql.Named(fmt.Sprintf("p%d", n+3), e.createdAt),
+			sql.Named(fmt.Sprintf("p%d", n+4), requestID),
+		)
+	}
+	_, err = db.ExecContext(ctx,
+		"INSERT INTO query_logs (query, response, created_at, request_id) VALUES "+strings.Join(values, ", "),
+		args...,
+	)
+	return err
+}
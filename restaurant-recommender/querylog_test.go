@@ -0,0 +1,193 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestInsertQueryLogs tests that a batch is written with a single multi-row INSERT.
+func TestInsertQueryLogs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO query_logs (query, response, created_at, request_id) VALUES (@p1, @p2, @p3, @p4), (@p5, @p6, @p7, @p8)")).
+		WithArgs("first", `{"a":1}`, now, sql.NullString{String: "req-1", Valid: true}, "second", `{"b":2}`, now, sql.NullString{}).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = insertQueryLogs(context.Background(), db, []queryLogEntry{
+		{query: "first", response: `{"a":1}`, createdAt: now, requestID: "req-1"},
+		{query: "second", response: `{"b":2}`, createdAt: now},
+	})
+	if err != nil {
+		t.Errorf("insertQueryLogs returned error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// TestQueryLogWriter tests that queued entries are batched and flushed on Close.
+func TestQueryLogWriter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	resp := Recommendation{RestaurantRecommendation: Restaurant{Name: "Pizza Hut"}}
+
+	// Two full batches of two, then the remaining entry when the writer closes.
+	anyArg := sqlmock.AnyArg()
+	mock.ExpectExec(regexp.QuoteMeta("VALUES (@p1, @p2, @p3, @p4), (@p5, @p6, @p7, @p8)")).
+		WithArgs("q1", anyArg, anyArg, anyArg, "q2", anyArg, anyArg, anyArg).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("VALUES (@p1, @p2, @p3, @p4), (@p5, @p6, @p7, @p8)")).
+		WithArgs("q3", anyArg, anyArg, anyArg, "q4", anyArg, anyArg, anyArg).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("VALUES (@p1, @p2, @p3, @p4)")).
+		WithArgs("q5", `{"restaurantRecommendation":{"id":"","name":"Pizza Hut","style":"","address":"","openHour":"","closeHour":"","vegetarian":false,"deliveries":false,"phone":"","website":"","email":"","seatingCapacity":0,"parking":false,"wifi":false,"wheelchairAccessible":false}}`, anyArg, sql.NullString{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	w := NewQueryLogWriter(db, QueryLogConfig{QueueSize: 10, BatchSize: 2, FlushInterval: time.Hour})
+	for _, q := range []string{"q1", "q2", "q3", "q4", "q5"} {
+		if !w.Log(context.Background(), q, resp) {
+			t.Errorf("expected %s to be queued", q)
+		}
+	}
+
+	if err := w.Close(context.Background()); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if w.Log(context.Background(), "late", resp) {
+		t.Error("expected entries logged after Close to be dropped")
+	}
+
+	stats := w.Stats()
+	if stats.Enqueued != 5 || stats.Written != 5 || stats.Dropped != 1 || stats.Failed != 0 || stats.Pending != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// TestQueryLogWriter_FlushInterval tests that a partial batch is written once the interval passes.
+func TestQueryLogWriter_FlushInterval(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO query_logs")).
+		WithArgs("only", sqlmock.AnyArg(), sqlmock.AnyArg(), "req-9").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	w := NewQueryLogWriter(db, QueryLogConfig{BatchSize: 100, FlushInterval: 10 * time.Millisecond})
+	defer w.Close(context.Background())
+	// The entry records the ID of the request that logged it.
+	w.Log(WithRequestID(context.Background(), "req-9"), "only", Recommendation{})
+
+	deadline := time.Now().Add(time.Second)
+	for w.Stats().Written == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// TestQueryLogWriter_Drops tests that entries are dropped, not blocked on, when the queue is full.
+func TestQueryLogWriter_Drops(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	// Hold the first batch in the database so the queue fills up behind it.
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO query_logs")).
+		WillDelayFor(100 * time.Millisecond).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO query_logs")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	w := NewQueryLogWriter(db, QueryLogConfig{QueueSize: 1, BatchSize: 1, FlushInterval: time.Hour})
+	w.Log(context.Background(), "in flight", Recommendation{})
+	for w.Stats().Pending != 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	start := time.Now()
+	queued := w.Log(context.Background(), "queued", Recommendation{})
+	dropped := w.Log(context.Background(), "dropped", Recommendation{})
+	if !queued || dropped {
+		t.Errorf("expected the second entry to be queued and the third dropped, got %v and %v", queued, dropped)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("expected Log not to block, took %v", elapsed)
+	}
+
+	if err := w.Close(context.Background()); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if stats := w.Stats(); stats.Written != 2 || stats.Dropped != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+// TestQueryLogWriter_InFlight tests that entries taken off the queue are
+// counted as in flight until their batch is written.
+func TestQueryLogWriter_InFlight(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	// Hold the batch in the database while the stats are read.
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO query_logs")).
+		WillDelayFor(200 * time.Millisecond).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	w := NewQueryLogWriter(db, QueryLogConfig{QueueSize: 10, BatchSize: 2, FlushInterval: time.Hour})
+	w.Log(context.Background(), "first", Recommendation{})
+	w.Log(context.Background(), "second", Recommendation{})
+
+	deadline := time.Now().Add(time.Second)
+	for w.Stats().Pending != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if stats := w.Stats(); stats.Pending != 0 || stats.InFlight != 2 || stats.Written != 0 {
+		t.Errorf("expected 2 entries in flight during the flush, got %+v", stats)
+	}
+
+	if err := w.Close(context.Background()); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if stats := w.Stats(); stats.InFlight != 0 || stats.Written != 2 {
+		t.Errorf("expected the batch to be written and nothing in flight, got %+v", stats)
+	}
+}
+
+// TestQueryLogWriter_Nil tests that a nil writer disables logging.
+func TestQueryLogWriter_Nil(t *testing.T) {
+	var w *QueryLogWriter
+	if w.Log(context.Background(), "query", Recommendation{}) {
+		t.Error("expected a nil writer to drop entries")
+	}
+	if err := w.Close(context.Background()); err != nil {
+		t.Errorf("expected Close on a nil writer to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Relaxation names a query constraint that was loosened to suggest an alternative.
+type Relaxation string
+
+// Constraints that can be relaxed when nothing matches a query exactly.
+const (
+	RelaxOpenTime Relaxation = "openTime" // open within openTimeTolerance of the requested time
+	RelaxDelivery Relaxation = "delivers" // does not deliver
+	RelaxWiFi     Relaxation = "wifi"     // has no wifi
+	RelaxParking  Relaxation = "parking"  // has no parking
+	RelaxStyle    Relaxation = "style"    // serves a similar cuisine
+)
+
+// relaxationOrder lists the relaxable constraints, least important first. Each
+// step keeps the relaxations before it. Vegetarian and accessibility
+// requirements are needs rather than preferences and are never relaxed.
+var relaxationOrder = []Relaxation{RelaxOpenTime, RelaxDelivery, RelaxWiFi, RelaxParking, RelaxStyle}
+
+// openTimeTolerance is how far from the requested time a restaurant may open
+// or close and still be suggested.
+const openTimeTolerance = 30 * time.Minute
+
+// openTimeStep is the granularity at which the tolerance window is checked.
+const openTimeStep = 5 * time.Minute
+
+// maxAlternatives bounds the number of alternatives suggested for a query.
+const maxAlternatives = 3
+
+// styleFamilies groups cuisines that can stand in for one another. Styles are
+// matched case-insensitively and a style may belong to several families.
+var styleFamilies = [][]string{
+	{"italian", "pizza", "mediterranean"},
+	{"greek", "turkish", "lebanese", "mediterranean"},
+	{"mexican", "tex-mex", "latin american", "spanish"},
+	{"japanese", "sushi", "ramen", "korean", "asian"},
+	{"chinese", "dim sum", "asian"},
+	{"thai", "vietnamese", "asian"},
+	{"indian", "pakistani", "nepalese"},
+	{"american", "burger", "diner", "steak house"},
+	{"french", "bistro"},
+}
+
+// Alternative is a restaurant suggested when nothing matches a query exactly,
+// with the constraints it does not meet.
+type Alternative struct {
+	Restaurant
+	Relaxed []Relaxation `json:"relaxed"`
+}
+
+// similarStyles returns the lower-case styles sharing a family with style.
+func similarStyles(style string) map[string]bool {
+	style = strings.ToLower(style)
+	similar := map[string]bool{}
+	for _, family := range styleFamilies {
+		for _, s := range family {
+			if s != style {
+				continue
+			}
+			for _, other := range family {
+				if other != style {
+					similar[other] = true
+				}
+			}
+		}
+	}
+	return similar
+}
+
+// applies reports whether relaxation loosens a constraint the criteria set.
+func (rl Relaxation) applies(c QueryCriteria) bool {
+	switch rl {
+	case RelaxOpenTime:
+		return c.OpenAt != nil || c.OpenNow
+	case RelaxDelivery:
+		return c.Delivers != nil
+	case RelaxWiFi:
+		return c.WiFi != nil
+	case RelaxParking:
+		return c.Parking != nil
+	case RelaxStyle:
+		return c.Style != "" && len(similarStyles(c.Style)) > 0
+	}
+	return false
+}
+
+// clear removes the constraint from the criteria.
+func (rl Relaxation) clear(c *QueryCriteria) {
+	switch rl {
+	case RelaxOpenTime:
+		c.OpenAt, c.OpenNow = nil, false
+	case RelaxDelivery:
+		c.Delivers = nil
+	case RelaxWiFi:
+		c.WiFi = nil
+	case RelaxParking:
+		c.Parking = nil
+	case RelaxStyle:
+		c.Style = ""
+	}
+}
+
+// meetsRelaxed reports whether r satisfies the relaxed form of the constraint:
+// open near the requested time, or serving a similar cuisine. Constraints that
+// are dropped outright always hold.
+func (rl Relaxation) meetsRelaxed(r Restaurant, c QueryCriteria, now time.Time) bool {
+	switch rl {
+	case RelaxOpenTime:
+		return openNear(r, c, now)
+	case RelaxStyle:
+		return strings.EqualFold(r.Style, c.Style) || similarStyles(c.Style)[strings.ToLower(r.Style)]
+	}
+	return true
+}
+
+// meets reports whether r satisfies the constraint exactly.
+func (rl Relaxation) meets(r Restaurant, c QueryCriteria, now time.Time) bool {
+	exact := QueryCriteria{}
+	switch rl {
+	case RelaxOpenTime:
+		exact.OpenAt, exact.OpenNow = c.OpenAt, c.OpenNow
+	case RelaxDelivery:
+		exact.Delivers = c.Delivers
+	case RelaxWiFi:
+		exact.WiFi = c.WiFi
+	case RelaxParking:
+		exact.Parking = c.Parking
+	case RelaxStyle:
+		exact.Style = c.Style
+	}
+	return restaurantMatchesCriteria(r, exact, now)
+}
+
+// openNear reports whether r is open within openTimeTolerance of the time the
+// criteria ask for. For "open now" only later times count, as a restaurant
+// that has just closed is no use.
+func openNear(r Restaurant, c QueryCriteria, now time.Time) bool {
+	at, from := now, -openTimeTolerance
+	if c.OpenAt != nil {
+		at = *c.OpenAt
+	} else {
+		from = 0
+	}
+	for d := from; d <= openTimeTolerance; d += openTimeStep {
+		if isOpen(r, at.Add(d)) {
+			return true
+		}
+	}
+	return false
+}
+
+// findAlternatives progressively relaxes the criteria, least important
+// constraint first, and returns up to maxAlternatives restaurants from the
+// first step that finds any, closest matches first. It is meant for queries
+// with no exact match and returns nil if relaxing does not help.
+func findAlternatives(ctx context.Context, db *sql.DB, cache *CatalogueCache, criteria QueryCriteria, now time.Time) ([]Alternative, error) {
+	var relaxed []Relaxation
+	for _, rl := range relaxationOrder {
+		if !rl.applies(criteria) {
+			continue
+		}
+		relaxed = append(relaxed, rl)
+
+		// Query with the relaxed constraints dropped, then keep the
+		// restaurants that meet their relaxed forms.
+		loose := criteria
+		for _, step := range relaxed {
+			step.clear(&loose)
+		}
+		candidates, err := matchingRestaurants(ctx, db, cache, loose, now)
+		if err != nil {
+			return nil, err
+		}
+
+		var alternatives []Alternative
+	candidate:
+		for _, r := range candidates {
+			alt := Alternative{Restaurant: r, Relaxed: []Relaxation{}}
+			for _, step := range relaxed {
+				if !step.meetsRelaxed(r, criteria, now) {
+					continue candidate
+				}
+				if !step.meets(r, criteria, now) {
+					alt.Relaxed = append(alt.Relaxed, step)
+				}
+			}
+			alternatives = append(alternatives, alt)
+		}
+		if len(alternatives) == 0 {
+			continue
+		}
+
+		sort.SliceStable(alternatives, func(i, j int) bool {
+			return len(alternatives[i].Relaxed) < len(alternatives[j].Relaxed)
+		})
+		if len(alternatives) > maxAlternatives {
+			alternatives = alternatives[:maxAlternatives]
+		}
+		return alternatives, nil
+	}
+	return nil, nil
+}
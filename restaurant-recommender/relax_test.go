@@ -0,0 +1,162 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestSimilarStyles tests that similar styles come from every family a style belongs to.
+func TestSimilarStyles(t *testing.T) {
+	got := similarStyles("Mediterranean")
+	for _, want := range []string{"italian", "pizza", "greek", "lebanese"} {
+		if !got[want] {
+			t.Errorf("expected %q to be similar to Mediterranean, got %v", want, got)
+		}
+	}
+	if got["mediterranean"] {
+		t.Error("a style should not be similar to itself")
+	}
+	if len(similarStyles("Klingon")) != 0 {
+		t.Error("expected no similar styles for an unknown style")
+	}
+}
+
+// TestOpenNear tests the tolerance window around the requested time.
+func TestOpenNear(t *testing.T) {
+	r := Restaurant{OpenHour: "09:00", CloseHour: "17:00"}
+	now := time.Date(2025, 3, 2, 8, 40, 0, 0, time.UTC)
+	at := func(hour, minute int) *time.Time {
+		t := time.Date(2025, 3, 2, hour, minute, 0, 0, time.UTC)
+		return &t
+	}
+
+	tests := []struct {
+		criteria QueryCriteria
+		want     bool
+	}{
+		{QueryCriteria{OpenAt: at(8, 45)}, true},  // opens 15 minutes later
+		{QueryCriteria{OpenAt: at(17, 20)}, true}, // closed 20 minutes earlier
+		{QueryCriteria{OpenAt: at(8, 0)}, false},
+		{QueryCriteria{OpenNow: true}, true}, // opens in 20 minutes
+	}
+	for _, tt := range tests {
+		if got := openNear(r, tt.criteria, now); got != tt.want {
+			t.Errorf("openNear(%+v) = %v, want %v", tt.criteria, got, tt.want)
+		}
+	}
+
+	// For "open now", having just closed does not count.
+	if openNear(r, QueryCriteria{OpenNow: true}, time.Date(2025, 3, 2, 17, 10, 0, 0, time.UTC)) {
+		t.Error("expected a restaurant that just closed not to be open near now")
+	}
+}
+
+// TestFindAlternatives tests the order constraints are relaxed in and how
+// alternatives are marked.
+func TestFindAlternatives(t *testing.T) {
+	yes := true
+	at := time.Date(2025, 3, 2, 8, 45, 0, 0, time.UTC)
+	early := at.Add(-time.Hour)
+	catalogue := []Restaurant{
+		{ID: "pizza", Name: "Pizza Place", Style: "Pizza", OpenHour: "08:00", CloseHour: "22:00", Vegetarian: true},
+		{ID: "early", Name: "Early Trattoria", Style: "Italian", OpenHour: "09:00", CloseHour: "22:00", Vegetarian: true, Deliveries: true},
+		{ID: "nodeliver", Name: "Trattoria", Style: "italian", OpenHour: "08:00", CloseHour: "22:00", Vegetarian: true},
+		{ID: "meat", Name: "Steak House", Style: "Italian", OpenHour: "08:00", CloseHour: "22:00", Deliveries: true},
+	}
+
+	tests := []struct {
+		name     string
+		criteria QueryCriteria
+		want     map[string][]Relaxation
+	}{
+		{
+			name:     "open time first",
+			criteria: QueryCriteria{Style: "Italian", Vegetarian: &yes, Delivers: &yes, OpenAt: &at},
+			want:     map[string][]Relaxation{"early": {RelaxOpenTime}},
+		},
+		{
+			name:     "amenities after delivery",
+			criteria: QueryCriteria{Style: "Italian", Vegetarian: &yes, Delivers: &yes, Parking: &yes},
+			want: map[string][]Relaxation{
+				"early":     {RelaxParking},
+				"nodeliver": {RelaxDelivery, RelaxParking},
+			},
+		},
+		{
+			name:     "similar style last",
+			criteria: QueryCriteria{Style: "Mediterranean", Vegetarian: &yes},
+			want: map[string][]Relaxation{
+				"pizza":     {RelaxStyle},
+				"early":     {RelaxStyle},
+				"nodeliver": {RelaxStyle},
+			},
+		},
+		{
+			name:     "delivery dropped",
+			criteria: QueryCriteria{Style: "Italian", Vegetarian: &yes, Delivers: &yes, OpenAt: &early},
+			want:     map[string][]Relaxation{"nodeliver": {RelaxOpenTime, RelaxDelivery}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewCatalogueCache(nil, time.Hour)
+			cache.restaurants, cache.loadedAt = catalogue, time.Now()
+			alternatives, err := findAlternatives(context.Background(), nil, cache, tt.criteria, at)
+			if err != nil {
+				t.Fatalf("findAlternatives returned error: %v", err)
+			}
+			var got map[string][]Relaxation
+			for _, alt := range alternatives {
+				if got == nil {
+					got = map[string][]Relaxation{}
+				}
+				got[alt.ID] = alt.Relaxed
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestFindAlternatives_Database tests that without a cache each relaxation
+// step queries with the relaxed constraints dropped.
+func TestFindAlternatives_Database(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	yes := true
+	criteria := QueryCriteria{Style: "Thai", Delivers: &yes}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT " + selectRestaurantColumns + " FROM restaurants WHERE style = @p1 ORDER BY id")).
+		WithArgs("Thai").
+		WillReturnRows(sqlmock.NewRows(restaurantRowColumns))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT " + selectRestaurantColumns + " FROM restaurants ORDER BY id")).
+		WillReturnRows(sqlmock.NewRows(restaurantRowColumns).
+			AddRow("5d6e7f80-9a0b-4c1d-8e2f-3a4b5c6d7e8f", "Pho", "Vietnamese", "1 Noodle Lane", "12:00", "22:00", false, true,
+				"", "", "", 20, false, false, false).
+			AddRow("3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70", "Burger Bar", "Burger", "2 Bun Street", "12:00", "22:00", false, true,
+				"", "", "", 20, false, false, false))
+
+	alternatives, err := findAlternatives(context.Background(), db, nil, criteria, time.Now())
+	if err != nil {
+		t.Fatalf("findAlternatives returned error: %v", err)
+	}
+	if len(alternatives) != 1 || alternatives[0].Name != "Pho" ||
+		!reflect.DeepEqual(alternatives[0].Relaxed, []Relaxation{RelaxStyle}) {
+		t.Errorf("unexpected alternatives %+v", alternatives)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet SQL expectations: %v", err)
+	}
+}
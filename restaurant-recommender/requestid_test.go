@@ -0,0 +1,91 @@
+package restaurantrecommender
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestRequestID tests that request IDs are propagated, or generated when missing or unusable.
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string // empty means a generated UUID
+	}{
+		{"propagated", "abc-123", "abc-123"},
+		{"missing", "", ""},
+		{"too long", strings.Repeat("a", 129), ""},
+		{"control characters", "abc\ninjected", ""},
+		{"spaces", "abc 123", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = RequestIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/recommend", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			got := rec.Header().Get(RequestIDHeader)
+			if got != seen {
+				t.Errorf("expected response header %q to match context %q", got, seen)
+			}
+			if tt.expected != "" && got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+			if tt.expected == "" {
+				if _, err := uuid.Parse(got); err != nil {
+					t.Errorf("expected a generated UUID, got %q", got)
+				}
+			}
+		})
+	}
+}
+
+// TestContextHandler tests that log records carry the request and trace IDs from their context.
+func TestContextHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil))).With("component", "test")
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(WithRequestID(context.Background(), "req-1"),
+		trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+
+	logger.InfoContext(ctx, "with context")
+	logger.Info("without context")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d", len(lines))
+	}
+
+	var with, without map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &with); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &without); err != nil {
+		t.Fatal(err)
+	}
+	if with["request_id"] != "req-1" || with["trace_id"] != traceID.String() || with["component"] != "test" {
+		t.Errorf("expected request, trace and logger attributes, got %v", with)
+	}
+	if _, ok := without["request_id"]; ok {
+		t.Errorf("expected no request_id without a context, got %v", without)
+	}
+}
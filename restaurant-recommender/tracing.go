@@ -0,0 +1,23 @@
+package restaurantrecommender
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans started by this package.
+const tracerName = "github.com/kuhlman-labs/restaurant-recommender/restaurant-recommender"
+
+// tracer starts this package's spans. It uses the global tracer provider, so
+// spans are dropped until the application installs one.
+var tracer = otel.Tracer(tracerName)
+
+// endSpan records err, if any, on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,166 @@
+package restaurantrecommender
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	spanRecorderOnce sync.Once
+	spanRecorder     *tracetest.SpanRecorder
+)
+
+// traceSpans installs a recording tracer provider, once for the whole test
+// binary since the package tracer binds to the first one installed, and
+// returns a context carrying a new root span and a function listing the
+// ended spans in that span's trace.
+func traceSpans(t *testing.T) (context.Context, func() []sdktrace.ReadOnlySpan) {
+	t.Helper()
+	spanRecorderOnce.Do(func() {
+		spanRecorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+	})
+
+	ctx, root := otel.Tracer("test").Start(context.Background(), t.Name())
+	t.Cleanup(func() { root.End() })
+	traceID := root.SpanContext().TraceID()
+
+	return ctx, func() []sdktrace.ReadOnlySpan {
+		var spans []sdktrace.ReadOnlySpan
+		for _, s := range spanRecorder.Ended() {
+			if s.SpanContext().TraceID() == traceID {
+				spans = append(spans, s)
+			}
+		}
+		return spans
+	}
+}
+
+// spanNamed returns the ended span with the given name.
+func spanNamed(t *testing.T, spans []sdktrace.ReadOnlySpan, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for _, s := range spans {
+		if s.Name() == name {
+			return s
+		}
+	}
+	t.Fatalf("no %q span among %d spans", name, len(spans))
+	return nil
+}
+
+// spanAttribute returns the value of a span attribute.
+func spanAttribute(s sdktrace.ReadOnlySpan, key string) attribute.Value {
+	for _, kv := range s.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+// TestRecommendHandler_Tracing tests the spans recorded for a recommendation.
+func TestRecommendHandler_Tracing(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT DISTINCT style FROM restaurants").
+		WillReturnRows(sqlmock.NewRows([]string{"style"}).AddRow("Italian"))
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE style = @p1 AND vegetarian = @p2")).
+		WithArgs("Italian", true).
+		WillReturnRows(sqlmock.NewRows(restaurantRowColumns).
+			AddRow("3f2c1a9e-5b7d-4e8f-9a10-2b3c4d5e6f70", "Test Restaurant", "Italian", "123 Main St", "09:00", "23:00", true, true,
+				"", "", "", 50, false, false, false))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO query_logs")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx, spans := traceSpans(t)
+	logs := NewQueryLogWriter(db, QueryLogConfig{FlushInterval: time.Hour})
+	req := httptest.NewRequest(http.MethodGet, "/recommend?query=vegetarian+italian", nil).WithContext(ctx)
+	RecommendHandler(db, nil, logs)(httptest.NewRecorder(), req)
+	if err := logs.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := spanNamed(t, spans(), "RecommendHandler")
+	if got := spanAttribute(handler, "recommendation.result").AsString(); got != "match" {
+		t.Errorf("expected match result, got %q", got)
+	}
+	if len(handler.Events()) != 1 || handler.Events()[0].Name != "query log queued" {
+		t.Errorf("expected a query log queued event, got %+v", handler.Events())
+	}
+
+	parse := spanNamed(t, spans(), "parseQuery")
+	if got := spanAttribute(parse, "criteria.style").AsString(); got != "Italian" {
+		t.Errorf("expected style attribute Italian, got %q", got)
+	}
+	if !spanAttribute(parse, "criteria.vegetarian").AsBool() {
+		t.Error("expected vegetarian attribute")
+	}
+
+	for _, name := range []string{"parseQuery", "getRestaurantStyles", "findRestaurants"} {
+		if s := spanNamed(t, spans(), name); s.Parent().SpanID() != handler.SpanContext().SpanID() {
+			t.Errorf("expected %s to be a child of RecommendHandler", name)
+		}
+	}
+
+	// The batch write runs in its own trace, linked to the request.
+	var flush sdktrace.ReadOnlySpan
+	for _, s := range spanRecorder.Ended() {
+		for _, link := range s.Links() {
+			if s.Name() == "flushQueryLogs" && link.SpanContext.SpanID() == handler.SpanContext().SpanID() {
+				flush = s
+			}
+		}
+	}
+	if flush == nil {
+		t.Fatal("expected a flushQueryLogs span linked to the request")
+	}
+	if flush.SpanContext().TraceID() == handler.SpanContext().TraceID() {
+		t.Error("expected the batch write to start a new trace")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// TestWithQueryTimeout_Tracing tests that failed data-layer calls mark their span as failed.
+func TestWithQueryTimeout_Tracing(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT DISTINCT style FROM restaurants").
+		WillReturnError(context.DeadlineExceeded)
+
+	ctx, spans := traceSpans(t)
+	if _, err := getRestaurantStyles(ctx, db); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	span := spanNamed(t, spans(), "getRestaurantStyles")
+	if span.Status().Code != codes.Error {
+		t.Errorf("expected error status, got %v", span.Status())
+	}
+	if span.SpanKind() != trace.SpanKindClient {
+		t.Errorf("expected client span, got %v", span.SpanKind())
+	}
+}
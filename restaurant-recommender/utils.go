@@ -1,6 +1,8 @@
 package restaurantrecommender
 
 import (
+	"errors"
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
@@ -32,6 +34,12 @@ func parseQuery(query string, styles []string) QueryCriteria {
 		criteria.Delivers = &val
 	}
 
+	// Check for amenity keywords such as "with parking" or "has wifi", and
+	// negations of them such as "no parking" or "not wheelchair accessible".
+	criteria.Parking = amenityFlag(lowerQuery, parkingPattern)
+	criteria.WiFi = amenityFlag(lowerQuery, wifiPattern)
+	criteria.Accessible = amenityFlag(lowerQuery, accessiblePattern)
+
 	// Check for "open now".
 	if strings.Contains(lowerQuery, "open now") {
 		criteria.OpenNow = true
@@ -59,11 +67,69 @@ func parseQuery(query string, styles []string) QueryCriteria {
 	return criteria
 }
 
+// Amenity patterns for parseQuery. The first group is a negation, and the
+// second, for parking, marks street parking, which is not an amenity of the
+// restaurant.
+var (
+	parkingPattern    = amenityPattern(`(street\s+)?parking`)
+	wifiPattern       = amenityPattern(`wi-?fi|wi fi`)
+	accessiblePattern = amenityPattern(`wheelchair(?:[ -]accessible)?|accessible`)
+)
+
+// amenityPattern matches the whole words of terms, optionally preceded by
+// "no", "not" or "without" and up to two qualifiers such as "free". Other
+// words between a negation and the terms end it, so neither "no street
+// parking" nor "no vegetarian parking" negates parking.
+func amenityPattern(terms string) *regexp.Regexp {
+	return regexp.MustCompile(`(?:\b(no|not|without)\s+(?:(?:free|any|on-?site|private|dedicated)\s+){0,2})?\b(?:` + terms + `)\b`)
+}
+
+// amenityFlag returns true if the query asks for the amenity matched by re,
+// false if it rules it out, and nil if it does not mention it.
+func amenityFlag(lowerQuery string, re *regexp.Regexp) *bool {
+	m := re.FindStringSubmatch(lowerQuery)
+	if m == nil || len(m) > 2 && m[2] != "" {
+		return nil
+	}
+	val := m[1] == ""
+	return &val
+}
+
 // parseTime converts a string (e.g., "09:00") to a time.Time object.
 func parseTime(tStr string) (time.Time, error) {
 	return time.Parse("15:04", tStr)
 }
 
+// validateHour checks that a string is a zero-padded 24-hour time such as "09:00".
+func validateHour(field, value string) error {
+	t, err := parseTime(value)
+	if err != nil || t.Format("15:04") != value {
+		return fmt.Errorf("%s must be a time in HH:MM format, got %q", field, value)
+	}
+	return nil
+}
+
+// validateRestaurant checks that a restaurant has the fields required to be stored.
+func validateRestaurant(r Restaurant) error {
+	var errs []error
+	if strings.TrimSpace(r.Name) == "" {
+		errs = append(errs, errors.New("name is required"))
+	}
+	if strings.TrimSpace(r.Style) == "" {
+		errs = append(errs, errors.New("style is required"))
+	}
+	if err := validateHour("openHour", r.OpenHour); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateHour("closeHour", r.CloseHour); err != nil {
+		errs = append(errs, err)
+	}
+	if r.SeatingCapacity < 0 {
+		errs = append(errs, errors.New("seatingCapacity must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
 // isOpen checks if a restaurant is open at the specified time.
 func isOpen(r Restaurant, currentTime time.Time) bool {
 	open, err := parseTime(r.OpenHour)
@@ -100,6 +166,15 @@ func restaurantMatchesCriteria(r Restaurant, criteria QueryCriteria, now time.Ti
 	if criteria.Delivers != nil && r.Deliveries != *criteria.Delivers {
 		return false
 	}
+	if criteria.Parking != nil && r.Parking != *criteria.Parking {
+		return false
+	}
+	if criteria.WiFi != nil && r.WiFi != *criteria.WiFi {
+		return false
+	}
+	if criteria.Accessible != nil && r.WheelchairAccessible != *criteria.Accessible {
+		return false
+	}
 	var checkTime time.Time
 	if criteria.OpenAt != nil {
 		checkTime = *criteria.OpenAt
@@ -1,6 +1,8 @@
 package restaurantrecommender
 
 import (
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -27,6 +29,66 @@ func TestParseQuery(t *testing.T) {
 	}
 }
 
+// TestParseQueryAmenities ensures that amenity keywords are detected.
+func TestParseQueryAmenities(t *testing.T) {
+	criteria := parseQuery("A Korean place with parking that has Wi-Fi and is wheelchair accessible", []string{"Korean"})
+
+	if criteria.Parking == nil || !*criteria.Parking {
+		t.Error("Expected Parking to be true")
+	}
+	if criteria.WiFi == nil || !*criteria.WiFi {
+		t.Error("Expected WiFi to be true")
+	}
+	if criteria.Accessible == nil || !*criteria.Accessible {
+		t.Error("Expected Accessible to be true")
+	}
+
+	criteria = parseQuery("Any Korean restaurant", []string{"Korean"})
+	if criteria.Parking != nil || criteria.WiFi != nil || criteria.Accessible != nil {
+		t.Error("Expected amenities to be unset")
+	}
+
+	tests := []struct {
+		query                     string
+		parking, wifi, accessible *bool
+	}{
+		{"somewhere with no parking", boolPtr(false), nil, nil},
+		{"street parking only", nil, nil, nil},
+		{"no street parking", nil, nil, nil},
+		{"korean, no vegetarian parking", boolPtr(true), nil, nil},
+		{"without any on-site parking", boolPtr(false), nil, nil},
+		{"no on-site parking", boolPtr(false), nil, nil},
+		{"not wheelchair accessible", nil, nil, boolPtr(false)},
+		{"the inaccessible one", nil, nil, nil},
+		{"without free wifi but with parking", boolPtr(true), boolPtr(false), nil},
+		{"not too far, accessible and with wi fi", nil, boolPtr(true), boolPtr(true)},
+		{"a parkingless wifiless diner", nil, nil, nil},
+	}
+	for _, tt := range tests {
+		criteria := parseQuery(tt.query, nil)
+		for _, f := range []struct {
+			name      string
+			got, want *bool
+		}{
+			{"Parking", criteria.Parking, tt.parking},
+			{"WiFi", criteria.WiFi, tt.wifi},
+			{"Accessible", criteria.Accessible, tt.accessible},
+		} {
+			if (f.got == nil) != (f.want == nil) || f.got != nil && *f.got != *f.want {
+				t.Errorf("%q: expected %s %v, got %v", tt.query, f.name, fmtBoolPtr(f.want), fmtBoolPtr(f.got))
+			}
+		}
+	}
+}
+
+// fmtBoolPtr formats an optional flag for test messages.
+func fmtBoolPtr(b *bool) string {
+	if b == nil {
+		return "unset"
+	}
+	return strconv.FormatBool(*b)
+}
+
 // TestParseTime checks that parsing the string time works.
 func TestParseTime(t *testing.T) {
 	tm, err := parseTime("09:00")
@@ -76,4 +138,32 @@ func TestRestaurantMatchesCriteria(t *testing.T) {
 	if restaurantMatchesCriteria(restaurant, criteriaNoMatch, now) {
 		t.Error("Expected restaurant not to match criteria but it did")
 	}
+
+	// Amenity criteria the restaurant does not offer should not match.
+	criteriaNoParking := QueryCriteria{
+		Style:   "Italian",
+		Parking: boolPtr(true),
+	}
+	if restaurantMatchesCriteria(restaurant, criteriaNoParking, now) {
+		t.Error("Expected restaurant without parking not to match criteria but it did")
+	}
+}
+
+// TestValidateRestaurant checks required fields and hour formats.
+func TestValidateRestaurant(t *testing.T) {
+	valid := Restaurant{Name: "Test Restaurant", Style: "Italian", OpenHour: "09:00", CloseHour: "23:00"}
+	if err := validateRestaurant(valid); err != nil {
+		t.Errorf("Expected valid restaurant, got error: %v", err)
+	}
+
+	invalid := Restaurant{OpenHour: "9:00", CloseHour: "25:00", SeatingCapacity: -1}
+	err := validateRestaurant(invalid)
+	if err == nil {
+		t.Fatal("Expected validation error, got nil")
+	}
+	for _, want := range []string{"name is required", "style is required", "openHour", "closeHour", "seatingCapacity"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %q, got %v", want, err)
+		}
+	}
 }
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/kuhlman-labs/restaurant-recommender/config"
+	restaurantrecommender "github.com/kuhlman-labs/restaurant-recommender/restaurant-recommender"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// unversionedDeprecatedSince is when the unversioned API paths were
+// deprecated in favour of /v1.
+var unversionedDeprecatedSince = time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+// newMux registers the service's routes on a dedicated ServeMux.
+func newMux(db *sql.DB, cache *restaurantrecommender.CatalogueCache, logs *restaurantrecommender.QueryLogWriter, readiness *restaurantrecommender.Readiness, cfg config.Config) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("GET /healthz", restaurantrecommender.LivenessHandler())
+	mux.Handle("GET /readyz", restaurantrecommender.ReadinessHandler(db, readiness))
+	if cfg.Features.Metrics {
+		mux.Handle("GET /metrics", promhttp.Handler())
+	}
+	mux.Handle("GET /openapi.json", restaurantrecommender.OpenAPIHandler())
+	mux.Handle("GET /docs", restaurantrecommender.DocsHandler())
+	recommendV1 := restaurantrecommender.RecommendHandler(db, cache, logs)
+	mux.Handle("GET /v1/recommend", recommendV1)
+	mux.Handle("GET /v2/recommend", restaurantrecommender.RecommendV2Handler(db, cache, logs))
+	mux.Handle("POST /v2/recommend/batch", restaurantrecommender.RecommendBatchHandler(db, cache, logs))
+	// The unversioned path serves /v1 unchanged for existing clients.
+	mux.Handle("/recommend", restaurantrecommender.Deprecated(unversionedDeprecatedSince, "/v1/recommend", recommendV1))
+	// The catalogue is public to browse, as over GraphQL.
+	mux.Handle("GET /restaurants", restaurantrecommender.ListRestaurantsHandler(db))
+	mux.Handle("GET /restaurants/{id}", restaurantrecommender.GetRestaurantHandler(db))
+	mux.Handle("POST /restaurants/{id}/feedback", restaurantrecommender.FeedbackHandler(db))
+	graphQL := restaurantrecommender.GraphQLHandler(db, cache, logs)
+	mux.Handle("GET /graphql", graphQL)
+	mux.Handle("POST /graphql", graphQL)
+
+	// Restaurant administration endpoints are only exposed when a token is configured.
+	if adminToken := cfg.Server.AdminToken; cfg.Features.Admin && adminToken != "" {
+		admin := func(h http.Handler) http.Handler { return restaurantrecommender.RequireToken(adminToken, h) }
+		// Writes invalidate the catalogue cache so recommendations see them immediately.
+		write := func(h http.Handler) http.Handler {
+			return admin(restaurantrecommender.InvalidateOnWrite(cache, h))
+		}
+		mux.Handle("POST /restaurants", write(restaurantrecommender.CreateRestaurantHandler(db)))
+		mux.Handle("POST /restaurants/import", write(restaurantrecommender.ImportRestaurantsHandler(db)))
+		mux.Handle("GET /restaurants/export", admin(restaurantrecommender.ExportRestaurantsHandler(db)))
+		mux.Handle("PUT /restaurants/{id}", write(restaurantrecommender.UpdateRestaurantHandler(db)))
+		mux.Handle("PATCH /restaurants/{id}", write(restaurantrecommender.PatchRestaurantHandler(db)))
+		mux.Handle("DELETE /restaurants/{id}", write(restaurantrecommender.DeleteRestaurantHandler(db)))
+	} else {
+		// Reserve the export path, which would otherwise be taken for a
+		// restaurant ID and rejected as malformed.
+		mux.Handle("GET /restaurants/export", http.NotFoundHandler())
+		slog.Info("Restaurant administration endpoints are disabled; they need the admin feature and ADMIN_TOKEN")
+	}
+	return mux
+}
+
+// newHandler wraps the routes with tracing, continuing any W3C trace context
+// sent by the caller, request IDs, access logs and request metrics.
+func newHandler(mux *http.ServeMux) http.Handler {
+	var h http.Handler = mux
+	h = restaurantrecommender.InstrumentHandler(h)
+	h = restaurantrecommender.LogRequests(h)
+	h = restaurantrecommender.RequestID(h)
+	return otelhttp.NewHandler(h, "http.server")
+}
+
+// newServer configures the HTTP server.
+func newServer(cfg config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.ListenAddr,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// serve runs srv until ctx is cancelled. It then fails readiness for the
+// configured shutdown delay, stops accepting connections and waits up to the
+// shutdown timeout for in-flight requests to finish.
+func serve(ctx context.Context, srv *http.Server, readiness *restaurantrecommender.Readiness, cfg config.ServerConfig) error {
+	ln, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return err
+	}
+	slog.Info("Restaurant recommendation service is listening", "addr", ln.Addr().String())
+
+	errs := make(chan error, 1)
+	go func() { errs <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	readiness.Drain()
+	if cfg.ShutdownDelay > 0 {
+		slog.Info("Shutting down; failing readiness", "delay", cfg.ShutdownDelay.String())
+		time.Sleep(cfg.ShutdownDelay)
+	}
+
+	slog.Info("Draining in-flight requests")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		srv.Close()
+		return fmt.Errorf("shutting down server: %w", err)
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}